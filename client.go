@@ -3,19 +3,24 @@ package dropbox
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"time"
 )
 
 // Client implements a Dropbox client. You may use the Files and Users
 // clients directly if preferred, however Client exposes them both.
 type Client struct {
 	*Config
-	Users   *Users
-	Files   *Files
-	Sharing *Sharing
+	Users          *Users
+	Files          *Files
+	Sharing        *Sharing
+	FileProperties *FileProperties
+	FileRequests   *FileRequests
 }
 
 // New client.
@@ -24,13 +29,23 @@ func New(config *Config) *Client {
 	c.Users = &Users{c}
 	c.Files = &Files{c}
 	c.Sharing = &Sharing{c}
+	c.FileProperties = &FileProperties{c}
+	c.FileRequests = &FileRequests{c}
 	return c
 }
 
 // call rpc style endpoint.
 func (c *Client) call(path string, in interface{}) (io.ReadCloser, error) {
-	url := "https://api.dropboxapi.com/2" + path
+	return c.rpc("https://api.dropboxapi.com/2"+path, in)
+}
+
+// callContent rpc style endpoint served from the content host.
+func (c *Client) callContent(path string, in interface{}) (io.ReadCloser, error) {
+	return c.rpc("https://content.dropboxapi.com/2"+path, in)
+}
 
+// rpc performs a request with a JSON body against url.
+func (c *Client) rpc(url string, in interface{}) (io.ReadCloser, error) {
 	body, err := json.Marshal(in)
 	if err != nil {
 		return nil, err
@@ -49,16 +64,46 @@ func (c *Client) call(path string, in interface{}) (io.ReadCloser, error) {
 
 // download style endpoint.
 func (c *Client) download(path string, in interface{}, r io.Reader) (io.ReadCloser, int64, error) {
+	req, err := c.newDownloadRequest(path, in, r)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return c.do(req)
+}
+
+// downloadResult download style endpoint, decoding the Dropbox-API-Result header into result.
+func (c *Client) downloadResult(path string, in interface{}, result interface{}) (io.ReadCloser, int64, error) {
+	req, err := c.newDownloadRequest(path, in, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res, err := c.send(req)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if err := json.Unmarshal([]byte(res.Header.Get("Dropbox-API-Result")), result); err != nil {
+		res.Body.Close()
+		return nil, 0, err
+	}
+
+	return res.Body, res.ContentLength, nil
+}
+
+// newDownloadRequest builds a content endpoint request with the arguments in a header.
+func (c *Client) newDownloadRequest(path string, in interface{}, r io.Reader) (*http.Request, error) {
 	url := "https://content.dropboxapi.com/2" + path
 
 	body, err := json.Marshal(in)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
 	req, err := http.NewRequest("POST", url, r)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
 	req.Header.Set("Dropbox-API-Arg", string(body))
@@ -67,18 +112,28 @@ func (c *Client) download(path string, in interface{}, r io.Reader) (io.ReadClos
 		req.Header.Set("Content-Type", "application/octet-stream")
 	}
 
-	return c.do(req)
+	return req, nil
 }
 
 // perform the request.
 func (c *Client) do(req *http.Request) (io.ReadCloser, int64, error) {
-	res, err := c.HTTPClient.Do(req)
+	res, err := c.send(req)
 	if err != nil {
 		return nil, 0, err
 	}
 
+	return res.Body, res.ContentLength, nil
+}
+
+// send the request, converting error responses to *Error.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	res, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
 	if res.StatusCode < 400 {
-		return res.Body, res.ContentLength, err
+		return res, err
 	}
 
 	defer res.Body.Close()
@@ -93,18 +148,87 @@ func (c *Client) do(req *http.Request) (io.ReadCloser, int64, error) {
 	if strings.Contains(kind, "text/plain") {
 		if b, err := ioutil.ReadAll(res.Body); err == nil {
 			e.Summary = string(b)
-			return nil, 0, e
+			return nil, e
 		}
-		return nil, 0, err
+		return nil, err
 	}
 
 	var errInfo errorInfo
 	if err := json.NewDecoder(res.Body).Decode(&errInfo); err != nil {
-		return nil, 0, err
+		return nil, err
+	}
+
+	var tag struct {
+		Tag string `json:".tag"`
+	}
+	if len(errInfo.Error) > 0 {
+		json.Unmarshal(errInfo.Error, &tag)
 	}
 
 	e.Summary = errInfo.Summary
-	e.Tag = errInfo.Error.Tag
+	e.Tag = tag.Tag
+	e.details = errInfo.Error
+
+	return nil, e
+}
 
-	return nil, 0, e
+// Backoff used when polling async jobs.
+var (
+	pollInterval    = 500 * time.Millisecond
+	pollMaxInterval = 8 * time.Second
+	pollMaxWait     = 30 * time.Minute
+)
+
+// ErrJobTimeout is returned when an async job doesn't finish within the
+// maximum wait of the *AndWait helpers.
+var ErrJobTimeout = errors.New("dropbox: timed out waiting for async job")
+
+// poll calls fn with exponential backoff until it reports done or fails,
+// giving up with ErrJobTimeout after pollMaxWait.
+func poll(fn func() (done bool, err error)) error {
+	deadline := time.Now().Add(pollMaxWait)
+	d := pollInterval
+	for {
+		if time.Now().Add(d).After(deadline) {
+			return ErrJobTimeout
+		}
+		time.Sleep(d)
+
+		done, err := fn()
+		if err != nil || done {
+			return err
+		}
+
+		if d *= 2; d > pollMaxInterval {
+			d = pollMaxInterval
+		}
+	}
+}
+
+// jobStatus is implemented by the outputs of the check job status endpoints.
+type jobStatus interface {
+	// status returns the job's tag and, when it failed, why.
+	status() (tag string, failed error)
+}
+
+// waitForJob polls check until the async job completes. A failed job returns
+// its error and an unknown status is treated as an error.
+func waitForJob(check func() (jobStatus, error)) error {
+	return poll(func() (bool, error) {
+		s, err := check()
+		if err != nil {
+			return false, err
+		}
+
+		tag, failed := s.status()
+		switch tag {
+		case AsyncJobInProgress:
+			return false, nil
+		case AsyncJobComplete:
+			return true, nil
+		case AsyncJobFailed:
+			return false, failed
+		}
+		return false, fmt.Errorf("dropbox: unknown job status %q", tag)
+	})
 }
@@ -2,6 +2,7 @@ package dropbox
 
 import (
 	"testing"
+	"time"
 
 	"github.com/segmentio/go-env"
 	"github.com/stretchr/testify/assert"
@@ -38,3 +39,42 @@ func TestClient_error_json(t *testing.T) {
 	assert.Equal(t, "Conflict", e.Status)
 	assert.Equal(t, 409, e.StatusCode)
 }
+
+func TestPoll_timeout(t *testing.T) {
+	defer func(i, m, w time.Duration) {
+		pollInterval, pollMaxInterval, pollMaxWait = i, m, w
+	}(pollInterval, pollMaxInterval, pollMaxWait)
+	pollInterval, pollMaxInterval, pollMaxWait = time.Millisecond, 2*time.Millisecond, 20*time.Millisecond
+
+	calls := 0
+	err := poll(func() (bool, error) {
+		calls++
+		return false, nil
+	})
+
+	assert.Equal(t, ErrJobTimeout, err)
+	assert.NotZero(t, calls)
+}
+
+func TestWaitForJob(t *testing.T) {
+	defer func(i time.Duration) { pollInterval = i }(pollInterval)
+	pollInterval = time.Millisecond
+
+	statuses := []*CheckJobStatusOutput{{Tag: AsyncJobInProgress}, {Tag: AsyncJobComplete}}
+	err := waitForJob(func() (jobStatus, error) {
+		s := statuses[0]
+		statuses = statuses[1:]
+		return s, nil
+	})
+	assert.NoError(t, err)
+
+	err = waitForJob(func() (jobStatus, error) {
+		return &CheckJobStatusOutput{Tag: AsyncJobFailed}, nil
+	})
+	assert.IsType(t, &SharingJobError{}, err)
+
+	err = waitForJob(func() (jobStatus, error) {
+		return &CheckJobStatusOutput{Tag: "other"}, nil
+	})
+	assert.EqualError(t, err, `dropbox: unknown job status "other"`)
+}
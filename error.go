@@ -1,13 +1,14 @@
 package dropbox
 
-import "fmt"
+import (
+	"encoding/json"
+	"fmt"
+)
 
 // errorInfo Dropbox error info.
 type errorInfo struct {
-	Summary string `json:"error_summary"`
-	Error   struct {
-		Tag string `json:".tag"`
-	} `json:"error"`
+	Summary string          `json:"error_summary"`
+	Error   json.RawMessage `json:"error"`
 }
 
 // Error response.
@@ -16,6 +17,15 @@ type Error struct {
 	StatusCode int
 	Summary    string
 	Tag        string
+	details    json.RawMessage
+}
+
+// decodeDetails decodes the endpoint specific error object into v.
+func (e *Error) decodeDetails(v interface{}) error {
+	if len(e.details) == 0 {
+		return fmt.Errorf("dropbox: error has no details")
+	}
+	return json.Unmarshal(e.details, v)
 }
 
 // Error string.
@@ -15,9 +15,11 @@ func Example() {
 	file, _ := os.Open("Readme.md")
 
 	d.Files.Upload(&dropbox.UploadInput{
-		Path:   "Readme.md",
+		CommitInfo: dropbox.CommitInfo{
+			Path: "Readme.md",
+			Mute: true,
+		},
 		Reader: file,
-		Mute:   true,
 	})
 }
 
@@ -0,0 +1,263 @@
+package dropbox
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// FileRequests client for file requests.
+type FileRequests struct {
+	*Client
+}
+
+// NewFileRequests client.
+func NewFileRequests(config *Config) *FileRequests {
+	return &FileRequests{
+		Client: &Client{
+			Config: config,
+		},
+	}
+}
+
+// GracePeriod determines how long uploads are allowed after the deadline.
+type GracePeriod string
+
+// GracePeriod types supported.
+const (
+	GracePeriodOneDay     GracePeriod = "one_day"
+	GracePeriodTwoDays    GracePeriod = "two_days"
+	GracePeriodSevenDays  GracePeriod = "seven_days"
+	GracePeriodThirtyDays GracePeriod = "thirty_days"
+	GracePeriodAlways     GracePeriod = "always"
+)
+
+// FileRequestGracePeriod specifies the grace period of a deadline.
+type FileRequestGracePeriod struct {
+	Tag GracePeriod `json:".tag"`
+}
+
+// FileRequestDeadline specifies the deadline of a file request.
+type FileRequestDeadline struct {
+	Deadline         time.Time               `json:"deadline"`
+	AllowLateUploads *FileRequestGracePeriod `json:"allow_late_uploads,omitempty"`
+}
+
+// NewFileRequestDeadline creates FileRequestDeadline. Dropbox only accepts
+// whole seconds, so the deadline is truncated. An empty grace period
+// disallows late uploads.
+func NewFileRequestDeadline(deadline time.Time, grace GracePeriod) *FileRequestDeadline {
+	d := &FileRequestDeadline{
+		Deadline: deadline.UTC().Truncate(time.Second),
+	}
+	if grace != "" {
+		d.AllowLateUploads = &FileRequestGracePeriod{grace}
+	}
+	return d
+}
+
+// FileRequest is a request for files to be uploaded to a folder.
+type FileRequest struct {
+	ID          string               `json:"id"`
+	URL         string               `json:"url"`
+	Title       string               `json:"title"`
+	Created     time.Time            `json:"created"`
+	IsOpen      bool                 `json:"is_open"`
+	FileCount   int64                `json:"file_count"`
+	Destination string               `json:"destination,omitempty"`
+	Deadline    *FileRequestDeadline `json:"deadline,omitempty"`
+	Description string               `json:"description,omitempty"`
+}
+
+// CreateFileRequestInput request input.
+type CreateFileRequestInput struct {
+	Title       string               `json:"title"`
+	Destination string               `json:"destination"`
+	Deadline    *FileRequestDeadline `json:"deadline,omitempty"`
+	Open        bool                 `json:"open"`
+	Description string               `json:"description,omitempty"`
+}
+
+// NewCreateFileRequestInput creates CreateFileRequestInput and set default values.
+func NewCreateFileRequestInput() *CreateFileRequestInput {
+	return &CreateFileRequestInput{
+		Open: true,
+	}
+}
+
+// CreateFileRequestOutput request output.
+type CreateFileRequestOutput struct {
+	FileRequest
+}
+
+// Create a file request for the current user.
+func (c *FileRequests) Create(in *CreateFileRequestInput) (out *CreateFileRequestOutput, err error) {
+	body, err := c.call("/file_requests/create", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// GetFileRequestInput request input.
+type GetFileRequestInput struct {
+	ID string `json:"id"`
+}
+
+// GetFileRequestOutput request output.
+type GetFileRequestOutput struct {
+	FileRequest
+}
+
+// Get a file request.
+func (c *FileRequests) Get(in *GetFileRequestInput) (out *GetFileRequestOutput, err error) {
+	body, err := c.call("/file_requests/get", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// UpdateFileRequestDeadline specifies how to change a deadline. Tag is
+// "no_update", or "update" with an optional new deadline, where an empty
+// deadline removes it.
+type UpdateFileRequestDeadline struct {
+	Tag              string                  `json:".tag"`
+	Deadline         *time.Time              `json:"deadline,omitempty"`
+	AllowLateUploads *FileRequestGracePeriod `json:"allow_late_uploads,omitempty"`
+}
+
+// NewUpdateFileRequestDeadline creates UpdateFileRequestDeadline setting the
+// deadline, or removing it when deadline is nil.
+func NewUpdateFileRequestDeadline(deadline *FileRequestDeadline) *UpdateFileRequestDeadline {
+	u := &UpdateFileRequestDeadline{Tag: "update"}
+	if deadline != nil {
+		u.Deadline = &deadline.Deadline
+		u.AllowLateUploads = deadline.AllowLateUploads
+	}
+	return u
+}
+
+// UpdateFileRequestInput request input.
+type UpdateFileRequestInput struct {
+	ID          string                     `json:"id"`
+	Title       string                     `json:"title,omitempty"`
+	Destination string                     `json:"destination,omitempty"`
+	Deadline    *UpdateFileRequestDeadline `json:"deadline,omitempty"`
+	Open        *bool                      `json:"open,omitempty"`
+	Description string                     `json:"description,omitempty"`
+}
+
+// UpdateFileRequestOutput request output.
+type UpdateFileRequestOutput struct {
+	FileRequest
+}
+
+// Update a file request. Empty fields are left unchanged.
+func (c *FileRequests) Update(in *UpdateFileRequestInput) (out *UpdateFileRequestOutput, err error) {
+	body, err := c.call("/file_requests/update", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// ListFileRequestsInput request input.
+type ListFileRequestsInput struct {
+	Limit uint64 `json:"limit,omitempty"`
+}
+
+// ListFileRequestsOutput request output.
+type ListFileRequestsOutput struct {
+	FileRequests []*FileRequest `json:"file_requests"`
+	Cursor       string         `json:"cursor"`
+	HasMore      bool           `json:"has_more"`
+}
+
+// List returns the file requests of the current user.
+func (c *FileRequests) List(in *ListFileRequestsInput) (out *ListFileRequestsOutput, err error) {
+	body, err := c.call("/file_requests/list_v2", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// ListFileRequestsContinueInput request input.
+type ListFileRequestsContinueInput struct {
+	Cursor string `json:"cursor"`
+}
+
+// ListContinue pagenates using the cursor from List.
+func (c *FileRequests) ListContinue(in *ListFileRequestsContinueInput) (out *ListFileRequestsOutput, err error) {
+	body, err := c.call("/file_requests/list/continue", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// CountFileRequestsOutput request output.
+type CountFileRequestsOutput struct {
+	FileRequestCount uint64 `json:"file_request_count"`
+}
+
+// Count returns the total number of file requests of the current user.
+func (c *FileRequests) Count() (out *CountFileRequestsOutput, err error) {
+	body, err := c.call("/file_requests/count", nil)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// DeleteFileRequestsInput request input.
+type DeleteFileRequestsInput struct {
+	IDs []string `json:"ids"`
+}
+
+// DeleteFileRequestsOutput request output.
+type DeleteFileRequestsOutput struct {
+	FileRequests []*FileRequest `json:"file_requests"`
+}
+
+// Delete closed file requests.
+func (c *FileRequests) Delete(in *DeleteFileRequestsInput) (out *DeleteFileRequestsOutput, err error) {
+	body, err := c.call("/file_requests/delete", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// DeleteAllClosed deletes all closed file requests of the current user.
+func (c *FileRequests) DeleteAllClosed() (out *DeleteFileRequestsOutput, err error) {
+	body, err := c.call("/file_requests/delete_all_closed", nil)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
@@ -0,0 +1,55 @@
+package dropbox
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileRequests_Create(t *testing.T) {
+	c := client()
+
+	in := NewCreateFileRequestInput()
+	in.Title = "Documents"
+	in.Destination = "/File Requests/Documents"
+	in.Deadline = NewFileRequestDeadline(time.Now().Add(24*time.Hour), GracePeriodOneDay)
+
+	out, err := c.FileRequests.Create(in)
+	assert.NoError(t, err)
+	assert.True(t, out.IsOpen)
+	assert.Equal(t, GracePeriodOneDay, out.Deadline.AllowLateUploads.Tag)
+
+	closed := false
+	_, err = c.FileRequests.Update(&UpdateFileRequestInput{
+		ID:       out.ID,
+		Deadline: NewUpdateFileRequestDeadline(nil),
+		Open:     &closed,
+	})
+	assert.NoError(t, err)
+
+	del, err := c.FileRequests.Delete(&DeleteFileRequestsInput{IDs: []string{out.ID}})
+	assert.NoError(t, err)
+	assert.Len(t, del.FileRequests, 1)
+}
+
+func TestFileRequests_List(t *testing.T) {
+	c := client()
+
+	count, err := c.FileRequests.Count()
+	assert.NoError(t, err)
+
+	out, err := c.FileRequests.List(&ListFileRequestsInput{Limit: 1})
+	assert.NoError(t, err)
+
+	n := len(out.FileRequests)
+	for out.HasMore {
+		out, err = c.FileRequests.ListContinue(&ListFileRequestsContinueInput{
+			Cursor: out.Cursor,
+		})
+		assert.NoError(t, err)
+		n += len(out.FileRequests)
+	}
+
+	assert.Equal(t, count.FileRequestCount, uint64(n))
+}
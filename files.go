@@ -1,12 +1,19 @@
 package dropbox
 
 import (
+	"archive/zip"
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"net/http"
 	"os"
+	pathpkg "path"
+	"path/filepath"
+	"regexp"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -108,7 +115,8 @@ type PropertyGroup struct {
 
 // FileExportInfo specifies export info.
 type FileExportInfo struct {
-	ExportAs string `json:"export_as"`
+	ExportAs      string   `json:"export_as,omitempty"`
+	ExportOptions []string `json:"export_options,omitempty"`
 }
 
 // FileSymlinkInfo specifies symlink info.
@@ -116,53 +124,261 @@ type FileSymlinkInfo struct {
 	Target string `json:"target"`
 }
 
+// FolderSharingInfo for a folder which is contained in a shared folder or
+// is itself a shared folder mount point.
+type FolderSharingInfo struct {
+	ReadOnly             bool   `json:"read_only"`
+	ParentSharedFolderID string `json:"parent_shared_folder_id,omitempty"`
+	SharedFolderID       string `json:"shared_folder_id,omitempty"`
+	TraverseOnly         bool   `json:"traverse_only"`
+	NoAccess             bool   `json:"no_access"`
+}
+
+// FileLockMetadata specifies the lock held on a file.
+type FileLockMetadata struct {
+	IsLockholder        bool      `json:"is_lockholder,omitempty"`
+	LockholderName      string    `json:"lockholder_name,omitempty"`
+	LockholderAccountID string    `json:"lockholder_account_id,omitempty"`
+	Created             time.Time `json:"created,omitempty"`
+}
+
+// Metadata types supported.
 const (
 	MetadataTypeFile    = "file"
 	MetadataTypeFolder  = "folder"
 	MetadataTypeDeleted = "deleted"
 )
 
-// Metadata for a file or folder.
-type Metadata struct {
-	Tag                      string           `json:".tag"`
-	Name                     string           `json:"name"`
-	PathLower                string           `json:"path_lower"`
-	PathDisplay              string           `json:"path_display"`
-	ClientModified           time.Time        `json:"client_modified"`
-	ServerModified           time.Time        `json:"server_modified"`
-	Rev                      string           `json:"rev"`
-	Size                     uint64           `json:"size"`
-	ID                       string           `json:"id"`
-	MediaInfo                *MediaInfo       `json:"media_info,omitempty"`
-	SymlinkInfo              *FileSymlinkInfo `json:"symlink_info,omitempty"`
-	SharingInfo              *FileSharingInfo `json:"sharing_info,omitempty"`
-	IsDownloadable           bool             `json:"is_downloadable"`
-	ExportInfo               *FileExportInfo  `json:"export_info,omitempty"`
-	PropertyGroups           []*PropertyGroup `json:"property_groups,omitempty"`
-	HasExplicitSharedMembers bool             `json:"has_explicit_shared_members,omitempty"`
-	ContentHash              string           `json:"content_hash,omitempty"`
-}
-
-// NewMetadata creates Metadata and set default values.
-func NewMetadata() *Metadata {
-	return &Metadata{
+// MetadataEntry is implemented by FileMetadata, FolderMetadata and DeletedMetadata.
+type MetadataEntry interface {
+	IsFile() bool
+	IsFolder() bool
+	IsDeleted() bool
+	Base() *BaseMetadata
+}
+
+// BaseMetadata holds the fields common to files, folders and deleted entries.
+type BaseMetadata struct {
+	Name                 string `json:"name"`
+	PathLower            string `json:"path_lower,omitempty"`
+	PathDisplay          string `json:"path_display,omitempty"`
+	ParentSharedFolderID string `json:"parent_shared_folder_id,omitempty"`
+}
+
+// Base returns the common metadata.
+func (m *BaseMetadata) Base() *BaseMetadata {
+	return m
+}
+
+// FileMetadata for a file.
+type FileMetadata struct {
+	BaseMetadata
+	ID                       string            `json:"id"`
+	ClientModified           time.Time         `json:"client_modified"`
+	ServerModified           time.Time         `json:"server_modified"`
+	Rev                      string            `json:"rev"`
+	Size                     uint64            `json:"size"`
+	MediaInfo                *MediaInfo        `json:"media_info,omitempty"`
+	SymlinkInfo              *FileSymlinkInfo  `json:"symlink_info,omitempty"`
+	SharingInfo              *FileSharingInfo  `json:"sharing_info,omitempty"`
+	IsDownloadable           bool              `json:"is_downloadable"`
+	ExportInfo               *FileExportInfo   `json:"export_info,omitempty"`
+	PropertyGroups           []*PropertyGroup  `json:"property_groups,omitempty"`
+	HasExplicitSharedMembers bool              `json:"has_explicit_shared_members,omitempty"`
+	ContentHash              string            `json:"content_hash,omitempty"`
+	FileLockInfo             *FileLockMetadata `json:"file_lock_info,omitempty"`
+	PreviewURL               string            `json:"preview_url,omitempty"`
+}
+
+// NewFileMetadata creates FileMetadata and set default values.
+func NewFileMetadata() *FileMetadata {
+	return &FileMetadata{
 		IsDownloadable: true,
 	}
 }
 
+// ExportFormats returns the formats a non-downloadable file can be exported
+// to, with the default format first, or nil if it can't be exported.
+func (m *FileMetadata) ExportFormats() []string {
+	if m.ExportInfo == nil {
+		return nil
+	}
+
+	var formats []string
+	if m.ExportInfo.ExportAs != "" {
+		formats = append(formats, m.ExportInfo.ExportAs)
+	}
+
+	for _, f := range m.ExportInfo.ExportOptions {
+		if f != m.ExportInfo.ExportAs {
+			formats = append(formats, f)
+		}
+	}
+
+	return formats
+}
+
+// IsFile returns true.
+func (m *FileMetadata) IsFile() bool { return true }
+
+// IsFolder returns false.
+func (m *FileMetadata) IsFolder() bool { return false }
+
+// IsDeleted returns false.
+func (m *FileMetadata) IsDeleted() bool { return false }
+
+// FolderMetadata for a folder.
+type FolderMetadata struct {
+	BaseMetadata
+	ID             string             `json:"id"`
+	SharedFolderID string             `json:"shared_folder_id,omitempty"` // Deprecated, use SharingInfo.
+	SharingInfo    *FolderSharingInfo `json:"sharing_info,omitempty"`
+	PropertyGroups []*PropertyGroup   `json:"property_groups,omitempty"`
+}
+
+// IsFile returns false.
+func (m *FolderMetadata) IsFile() bool { return false }
+
+// IsFolder returns true.
+func (m *FolderMetadata) IsFolder() bool { return true }
+
+// IsDeleted returns false.
+func (m *FolderMetadata) IsDeleted() bool { return false }
+
+// DeletedMetadata for a deleted file or folder.
+type DeletedMetadata struct {
+	BaseMetadata
+}
+
+// IsFile returns false.
+func (m *DeletedMetadata) IsFile() bool { return false }
+
+// IsFolder returns false.
+func (m *DeletedMetadata) IsFolder() bool { return false }
+
+// IsDeleted returns true.
+func (m *DeletedMetadata) IsDeleted() bool { return true }
+
+// Metadata for a file, folder or deleted entry, decoded by its ".tag".
+// Entries with a tag this package doesn't know have a nil Entry; their tag
+// and JSON are kept so Tag, Base and MarshalJSON still work.
+type Metadata struct {
+	Entry MetadataEntry
+	tag   string
+	raw   json.RawMessage
+}
+
+// UnmarshalJSON decodes the entry matching the ".tag" field.
+func (m *Metadata) UnmarshalJSON(b []byte) error {
+	var t struct {
+		Tag string `json:".tag"`
+	}
+	if err := json.Unmarshal(b, &t); err != nil {
+		return err
+	}
+
+	m.tag = t.Tag
+	m.raw = nil
+
+	switch strings.ToLower(t.Tag) {
+	case MetadataTypeFile:
+		m.Entry = NewFileMetadata()
+	case MetadataTypeFolder:
+		m.Entry = &FolderMetadata{}
+	case MetadataTypeDeleted:
+		m.Entry = &DeletedMetadata{}
+	default:
+		m.Entry = nil
+		m.raw = append(json.RawMessage(nil), b...)
+		return nil
+	}
+
+	return json.Unmarshal(b, m.Entry)
+}
+
+// MarshalJSON encodes the entry's fields along with its ".tag", so the
+// output decodes back into the same entry.
+func (m Metadata) MarshalJSON() ([]byte, error) {
+	if m.Entry == nil {
+		if m.raw != nil {
+			return m.raw, nil
+		}
+		return []byte("null"), nil
+	}
+
+	b, err := json.Marshal(m.Entry)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	if fields[".tag"], err = json.Marshal(m.Tag()); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(fields)
+}
+
+// Tag returns the ".tag" of the entry.
+func (m *Metadata) Tag() string {
+	switch {
+	case m.IsFile():
+		return MetadataTypeFile
+	case m.IsFolder():
+		return MetadataTypeFolder
+	case m.IsDeleted():
+		return MetadataTypeDeleted
+	}
+	return m.tag
+}
+
 // IsFile returns true if 'm' is file object
 func (m *Metadata) IsFile() bool {
-	return (strings.ToLower(m.Tag) == MetadataTypeFile)
+	return m.Entry != nil && m.Entry.IsFile()
 }
 
 // IsFolder returns true if 'm' is folder object
 func (m *Metadata) IsFolder() bool {
-	return (strings.ToLower(m.Tag) == MetadataTypeFolder)
+	return m.Entry != nil && m.Entry.IsFolder()
 }
 
 // IsDeleted returns true if 'm' is deleted object
 func (m *Metadata) IsDeleted() bool {
-	return (strings.ToLower(m.Tag) == MetadataTypeDeleted)
+	return m.Entry != nil && m.Entry.IsDeleted()
+}
+
+// File returns the file metadata, or nil if 'm' is not a file.
+func (m *Metadata) File() *FileMetadata {
+	f, _ := m.Entry.(*FileMetadata)
+	return f
+}
+
+// Folder returns the folder metadata, or nil if 'm' is not a folder.
+func (m *Metadata) Folder() *FolderMetadata {
+	f, _ := m.Entry.(*FolderMetadata)
+	return f
+}
+
+// Deleted returns the deleted metadata, or nil if 'm' is not deleted.
+func (m *Metadata) Deleted() *DeletedMetadata {
+	d, _ := m.Entry.(*DeletedMetadata)
+	return d
+}
+
+// Base returns the fields common to all entries, or an empty value if 'm' was not decoded.
+func (m *Metadata) Base() *BaseMetadata {
+	if m.Entry == nil {
+		b := &BaseMetadata{}
+		if m.raw != nil {
+			json.Unmarshal(m.raw, b)
+		}
+		return b
+	}
+	return m.Entry.Base()
 }
 
 // MetadataV2 metadata for a file, folder or deleted.
@@ -173,18 +389,23 @@ type MetadataV2 struct {
 // NewMetadataV2 creates MetadataV2 and set default values.
 func NewMetadataV2() *MetadataV2 {
 	return &MetadataV2{
-		Metadata: NewMetadata(),
+		Metadata: &Metadata{},
 	}
 }
 
-// TemplateFilterBase specifies template filter base.
+// TemplateFilterBase specifies template filter base. Tag is "filter_some"
+// to only include the templates in FilterSome, or "filter_none".
 type TemplateFilterBase struct {
-	FilterSome []string `json:"filter_some"`
+	Tag        string   `json:".tag"`
+	FilterSome []string `json:"filter_some,omitempty"`
 }
 
 // NewTemplateFilterBase creates TemplateFilterBase
-func NewTemplateFilterBase() *TemplateFilterBase {
-	return &TemplateFilterBase{}
+func NewTemplateFilterBase(templateIDs ...string) *TemplateFilterBase {
+	return &TemplateFilterBase{
+		Tag:        "filter_some",
+		FilterSome: templateIDs,
+	}
 }
 
 // GetMetadataInput request input.
@@ -336,7 +557,7 @@ type RestoreInput struct {
 
 // RestoreOutput request output.
 type RestoreOutput struct {
-	Metadata
+	FileMetadata
 }
 
 // Restore a file to a specific revision.
@@ -353,13 +574,20 @@ func (c *Files) Restore(in *RestoreInput) (out *RestoreOutput, err error) {
 
 // ListFolderInput request input.
 type ListFolderInput struct {
-	Path                            string `json:"path"`
-	Recursive                       bool   `json:"recursive"`
-	IncludeMediaInfo                bool   `json:"include_media_info"`
-	IncludeDeleted                  bool   `json:"include_deleted"`
-	IncludeHasExplicitSharedMembers bool   `json:"include_has_explicit_shared_members"`
-	IncludeMountedFolders           bool   `json:"include_mounted_folders"`
-	IncludeNonDownloadableFiles     bool   `json:"include_non_downloadable_files"`
+	Path                            string      `json:"path"`
+	Recursive                       bool        `json:"recursive"`
+	IncludeMediaInfo                bool        `json:"include_media_info"`
+	IncludeDeleted                  bool        `json:"include_deleted"`
+	IncludeHasExplicitSharedMembers bool        `json:"include_has_explicit_shared_members"`
+	IncludeMountedFolders           bool        `json:"include_mounted_folders"`
+	IncludeNonDownloadableFiles     bool        `json:"include_non_downloadable_files"`
+	SharedLink                      *SharedLink `json:"shared_link,omitempty"` // Path is then relative to the shared link.
+}
+
+// SharedLink specifies a shared link and its password, if any.
+type SharedLink struct {
+	URL      string `json:"url"`
+	Password string `json:"password,omitempty"`
 }
 
 // NewListFolderInput creates new ListFolderInput and set default values
@@ -414,12 +642,65 @@ type HighlightSpan struct {
 	IsHighlighted bool   `json:"is_highlighted"`
 }
 
+// SearchMatchType determines which part of a file or folder matched the query.
+type SearchMatchType string
+
+// SearchMatchType types supported.
+const (
+	SearchMatchTypeFilename           SearchMatchType = "filename"
+	SearchMatchTypeFileContent        SearchMatchType = "file_content"
+	SearchMatchTypeFilenameAndContent SearchMatchType = "filename_and_content"
+	SearchMatchTypeImageContent       SearchMatchType = "image_content"
+)
+
 // SearchMatchV2 represents a matched file, folder or deleted.
 type SearchMatchV2 struct {
 	MetadataV2
+	MatchType struct {
+		Tag SearchMatchType `json:".tag"`
+	} `json:"match_type"`
 	HighlightSpans []*HighlightSpan `json:"highlight_spans"`
 }
 
+// searchMetadata is the "metadata" union of a search match, whose only
+// variant wraps the entry's Metadata.
+type searchMetadata struct {
+	Tag      string    `json:".tag"`
+	Metadata *Metadata `json:"metadata"`
+}
+
+// UnmarshalJSON unwraps the match's "metadata" union into Metadata.
+func (m *SearchMatchV2) UnmarshalJSON(b []byte) error {
+	type match SearchMatchV2
+	v := struct {
+		*match
+		Metadata *searchMetadata `json:"metadata"`
+	}{match: (*match)(m)}
+
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	m.Metadata = nil
+	if v.Metadata != nil {
+		m.Metadata = v.Metadata.Metadata
+	}
+
+	return nil
+}
+
+// MarshalJSON wraps Metadata in the match's "metadata" union again.
+func (m SearchMatchV2) MarshalJSON() ([]byte, error) {
+	type match SearchMatchV2
+	return json.Marshal(struct {
+		match
+		Metadata *searchMetadata `json:"metadata"`
+	}{
+		match:    match(m),
+		Metadata: &searchMetadata{Tag: "metadata", Metadata: m.Metadata},
+	})
+}
+
 // FileStatusType file status types.
 type FileStatusType string
 
@@ -444,14 +725,24 @@ const (
 	FileCategoryOthers       FileCategoryType = "others"       // any file not in one of the categories above.
 )
 
+// SearchOrderBy determines the order of search results.
+type SearchOrderBy string
+
+const (
+	SearchOrderByRelevance        SearchOrderBy = "relevance"
+	SearchOrderByLastModifiedTime SearchOrderBy = "last_modified_time"
+)
+
 // SearchOptions represents search options.
 type SearchOptions struct {
 	Path           string             `json:"path,omitempty"`
 	MaxResults     uint64             `json:"max_results"` // min=1, max=1000
+	OrderBy        SearchOrderBy      `json:"order_by,omitempty"`
 	FileStatus     FileStatusType     `json:"file_status"`
 	FilenameOnly   bool               `json:"filename_only,omitempty"`
 	FileExtensions []string           `json:"file_extensions,omitempty"`
 	FileCategories []FileCategoryType `json:"file_categories,omitempty"`
+	AccountID      string             `json:"account_id,omitempty"` // Restrict results to files last modified by this account.
 }
 
 // NewSearchOptions creates new SearchOptions and set default values
@@ -462,11 +753,17 @@ func NewSearchOptions() *SearchOptions {
 	}
 }
 
+// SearchMatchFieldOptions represents options for the returned match fields.
+type SearchMatchFieldOptions struct {
+	IncludeHighlights bool `json:"include_highlights"`
+}
+
 // SearchInput request input.
 type SearchInput struct {
-	Query             string         `json:"query"`
-	Options           *SearchOptions `json:"options,omitempty"`
-	IncludeHighlights bool           `json:"include_highlights,omitempty"`
+	Query             string                   `json:"query"`
+	Options           *SearchOptions           `json:"options,omitempty"`
+	MatchFieldOptions *SearchMatchFieldOptions `json:"match_field_options,omitempty"`
+	IncludeHighlights bool                     `json:"include_highlights,omitempty"` // Deprecated, use MatchFieldOptions.
 }
 
 // SearchOutput request output.
@@ -509,6 +806,70 @@ func (c *Files) SearchContinue(in *SearchContinueInput) (out *SearchOutput, err
 	return
 }
 
+// SearchIterator iterates over search matches across pages.
+type SearchIterator struct {
+	files *Files
+	in    *SearchInput
+	max   int
+	count int
+	page  []*SearchMatchV2
+	out   *SearchOutput
+	match *SearchMatchV2
+	err   error
+}
+
+// SearchIter returns an iterator over the matches of in, fetching further
+// pages with SearchContinue. At most max matches are returned, or all
+// matches when max is 0.
+func (c *Files) SearchIter(in *SearchInput, max int) *SearchIterator {
+	return &SearchIterator{
+		files: c,
+		in:    in,
+		max:   max,
+	}
+}
+
+// Next advances to the next match, returning false when there are no more
+// matches or an error occurred.
+func (it *SearchIterator) Next() bool {
+	if it.err != nil || (it.max > 0 && it.count >= it.max) {
+		return false
+	}
+
+	for len(it.page) == 0 {
+		switch {
+		case it.out == nil:
+			it.out, it.err = it.files.Search(it.in)
+		case it.out.HasMore:
+			it.out, it.err = it.files.SearchContinue(&SearchContinueInput{
+				Cursor: it.out.Cursor,
+			})
+		default:
+			return false
+		}
+
+		if it.err != nil {
+			return false
+		}
+
+		it.page = it.out.Matches
+	}
+
+	it.match, it.page = it.page[0], it.page[1:]
+	it.count++
+	return true
+}
+
+// Match returns the current match.
+func (it *SearchIterator) Match() *SearchMatchV2 {
+	return it.match
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *SearchIterator) Err() error {
+	return it.err
+}
+
 // CommitInfo commit info struct
 type CommitInfo struct {
 	Path           string           `json:"path"`
@@ -568,7 +929,7 @@ func NewUploadInput() *UploadInput {
 
 // UploadOutput request output.
 type UploadOutput struct {
-	Metadata
+	FileMetadata
 }
 
 // Upload a file smaller than 150MB.
@@ -592,18 +953,20 @@ type DownloadInput struct {
 
 // DownloadOutput request output.
 type DownloadOutput struct {
-	Body   io.ReadCloser
-	Length int64
+	Body     io.ReadCloser `json:"-"`
+	Length   int64         `json:"-"`
+	Metadata *FileMetadata `json:"-"`
 }
 
 // Download a file.
 func (c *Files) Download(in *DownloadInput) (out *DownloadOutput, err error) {
-	body, l, err := c.download("/files/download", in, nil)
+	meta := NewFileMetadata()
+	body, l, err := c.downloadResult("/files/download", in, meta)
 	if err != nil {
 		return
 	}
 
-	out = &DownloadOutput{body, l}
+	out = &DownloadOutput{body, l, meta}
 	return
 }
 
@@ -615,8 +978,22 @@ const (
 	ThumbnailFormatJPEG ThumbnailFormat = "jpeg"
 	// ThumbnailFormatPNG specifies a PNG thumbnail
 	ThumbnailFormatPNG ThumbnailFormat = "png"
+	// ThumbnailFormatWebP specifies a WebP thumbnail
+	ThumbnailFormatWebP ThumbnailFormat = "webp"
 )
 
+// ContentType returns the MIME type of thumbnails in format 'f'.
+func (f ThumbnailFormat) ContentType() string {
+	switch f {
+	case ThumbnailFormatPNG:
+		return "image/png"
+	case ThumbnailFormatWebP:
+		return "image/webp"
+	default:
+		return "image/jpeg"
+	}
+}
+
 // ThumbnailSize determines the size of the thumbnail.
 type ThumbnailSize string
 
@@ -688,6 +1065,82 @@ func (c *Files) GetThumbnail(in *GetThumbnailInput) (out *GetThumbnailOutput, er
 	return
 }
 
+// ThumbnailV2Resource determines the file a v2 thumbnail is generated for.
+// Tag is "path" for a file in the user's Dropbox, or "link" for a file
+// referenced by shared link, where Path is relative to a shared folder link.
+type ThumbnailV2Resource struct {
+	Tag      string `json:".tag"`
+	Path     string `json:"path,omitempty"`
+	URL      string `json:"url,omitempty"`
+	Password string `json:"password,omitempty"`
+}
+
+// NewThumbnailV2PathResource creates ThumbnailV2Resource for a path or ID.
+func NewThumbnailV2PathResource(path string) *ThumbnailV2Resource {
+	return &ThumbnailV2Resource{
+		Tag:  "path",
+		Path: path,
+	}
+}
+
+// NewThumbnailV2LinkResource creates ThumbnailV2Resource for a shared link.
+func NewThumbnailV2LinkResource(url string) *ThumbnailV2Resource {
+	return &ThumbnailV2Resource{
+		Tag: "link",
+		URL: url,
+	}
+}
+
+// GetThumbnailV2Input request input.
+type GetThumbnailV2Input struct {
+	Resource *ThumbnailV2Resource `json:"resource"`
+	Format   ThumbnailFormat      `json:"format"`
+	Size     ThumbnailSize        `json:"size"`
+	Mode     ThumbnailMode        `json:"mode"`
+}
+
+// NewGetThumbnailV2Input creates GetThumbnailV2Input and set default values.
+func NewGetThumbnailV2Input(resource *ThumbnailV2Resource) *GetThumbnailV2Input {
+	return &GetThumbnailV2Input{
+		Resource: resource,
+		Format:   ThumbnailFormatJPEG,
+		Size:     ThumbnailSizeW64H64,
+		Mode:     ThumbnailModeStrict,
+	}
+}
+
+// MinimalFileLinkMetadata specifies the file a shared link thumbnail was generated for.
+type MinimalFileLinkMetadata struct {
+	URL  string `json:"url"`
+	Rev  string `json:"rev"`
+	ID   string `json:"id,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+// GetThumbnailV2Output request output. FileMetadata is set for path
+// resources and LinkMetadata for link resources.
+type GetThumbnailV2Output struct {
+	Body         io.ReadCloser            `json:"-"`
+	Length       int64                    `json:"-"`
+	ContentType  string                   `json:"-"`
+	FileMetadata *FileMetadata            `json:"file_metadata,omitempty"`
+	LinkMetadata *MinimalFileLinkMetadata `json:"link_metadata,omitempty"`
+}
+
+// GetThumbnailV2 a thumbnail for a file referenced by path, ID or shared link.
+func (c *Files) GetThumbnailV2(in *GetThumbnailV2Input) (out *GetThumbnailV2Output, err error) {
+	out = &GetThumbnailV2Output{}
+	body, l, err := c.downloadResult("/files/get_thumbnail_v2", in, out)
+	if err != nil {
+		return nil, err
+	}
+
+	out.Body = body
+	out.Length = l
+	out.ContentType = in.Format.ContentType()
+	return
+}
+
 // GetPreviewInput request input.
 type GetPreviewInput struct {
 	Path string `json:"path"`
@@ -724,9 +1177,10 @@ const (
 
 // ListRevisionsInput request input.
 type ListRevisionsInput struct {
-	Path  string            `json:"path"`
-	Mode  ListRevisionsMode `json:"mode,omitempty"`
-	Limit uint64            `json:"limit,omitempty"`
+	Path      string            `json:"path"`
+	Mode      ListRevisionsMode `json:"mode,omitempty"`
+	Limit     uint64            `json:"limit,omitempty"` // min=1, max=100
+	BeforeRev string            `json:"before_rev,omitempty"`
 }
 
 // NewListRevisionsInput creates ListRevisionsInput and set default values.
@@ -739,9 +1193,10 @@ func NewListRevisionsInput() *ListRevisionsInput {
 
 // ListRevisionsOutput request output.
 type ListRevisionsOutput struct {
-	IsDeleted     bool        `json:"is_deleted"`
-	Entries       []*Metadata `json:"entries"`
-	ServerDeleted *time.Time  `json:"server_deleted"`
+	IsDeleted     bool            `json:"is_deleted"`
+	Entries       []*FileMetadata `json:"entries"`
+	ServerDeleted *time.Time      `json:"server_deleted"`
+	HasMore       bool            `json:"has_more"`
 }
 
 // ListRevisions gets the revisions of the specified file.
@@ -756,6 +1211,70 @@ func (c *Files) ListRevisions(in *ListRevisionsInput) (out *ListRevisionsOutput,
 	return
 }
 
+// RevisionIterator iterates over the revisions of a file, newest first.
+type RevisionIterator struct {
+	files *Files
+	in    ListRevisionsInput
+	page  []*FileMetadata
+	out   *ListRevisionsOutput
+	rev   *FileMetadata
+	err   error
+}
+
+// ListRevisionsIter returns an iterator over all revisions of the file in
+// 'in', fetching older pages using BeforeRev.
+func (c *Files) ListRevisionsIter(in *ListRevisionsInput) *RevisionIterator {
+	return &RevisionIterator{
+		files: c,
+		in:    *in,
+	}
+}
+
+// Next advances to the next revision, returning false when there are no more
+// revisions or an error occurred.
+func (it *RevisionIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	for len(it.page) == 0 {
+		if it.out != nil {
+			if !it.out.HasMore || it.rev == nil {
+				return false
+			}
+			it.in.BeforeRev = it.rev.Rev
+		}
+
+		it.out, it.err = it.files.ListRevisions(&it.in)
+		if it.err != nil {
+			return false
+		}
+
+		it.page = it.out.Entries
+		if len(it.page) == 0 {
+			return false
+		}
+	}
+
+	it.rev, it.page = it.page[0], it.page[1:]
+	return true
+}
+
+// Revision returns the current revision.
+func (it *RevisionIterator) Revision() *FileMetadata {
+	return it.rev
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *RevisionIterator) Err() error {
+	return it.err
+}
+
+// DownloadRevision downloads a specific revision of a file.
+func (c *Files) DownloadRevision(rev string) (*DownloadOutput, error) {
+	return c.Download(&DownloadInput{Path: "rev:" + rev})
+}
+
 // Normalize path so people can use "/" as they expect.
 func normalizePath(s string) string {
 	if s == "/" {
@@ -837,3 +1356,1136 @@ func (c *Files) GetTemporaryUploadLink(in *GetTemporaryUploadLinkInput) (out *Ge
 	err = json.NewDecoder(body).Decode(&out)
 	return
 }
+
+// Async job states supported.
+const (
+	AsyncJobID         = "async_job_id"
+	AsyncJobInProgress = "in_progress"
+	AsyncJobComplete   = "complete"
+	AsyncJobFailed     = "failed"
+)
+
+// SaveURLInput request input.
+type SaveURLInput struct {
+	Path string `json:"path"`
+	URL  string `json:"url"`
+}
+
+// SaveURLOutput request output. Tag is either AsyncJobID or AsyncJobComplete.
+type SaveURLOutput struct {
+	Tag        string        `json:".tag"`
+	AsyncJobID string        `json:"async_job_id,omitempty"`
+	Complete   *FileMetadata `json:"-"`
+}
+
+// UnmarshalJSON decodes the completed file metadata when present.
+func (o *SaveURLOutput) UnmarshalJSON(b []byte) error {
+	type output SaveURLOutput
+	if err := json.Unmarshal(b, (*output)(o)); err != nil {
+		return err
+	}
+
+	if o.Tag != AsyncJobComplete {
+		return nil
+	}
+
+	o.Complete = NewFileMetadata()
+	return json.Unmarshal(b, o.Complete)
+}
+
+// SaveURL saves the data from a URL to a file in Dropbox. The download
+// happens server-side and usually completes asynchronously.
+func (c *Files) SaveURL(in *SaveURLInput) (out *SaveURLOutput, err error) {
+	body, err := c.call("/files/save_url", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// SaveURLErrorTag determines why a save_url job failed.
+type SaveURLErrorTag string
+
+// SaveURLErrorTag types supported.
+const (
+	SaveURLErrorPath           SaveURLErrorTag = "path"
+	SaveURLErrorDownloadFailed SaveURLErrorTag = "download_failed"
+	SaveURLErrorInvalidURL     SaveURLErrorTag = "invalid_url"
+	SaveURLErrorNotFound       SaveURLErrorTag = "not_found"
+)
+
+// SaveURLError is returned when a save_url job fails.
+type SaveURLError struct {
+	Tag  SaveURLErrorTag `json:".tag"`
+	Path struct {
+		Tag string `json:".tag"`
+	} `json:"path"`
+}
+
+// Error string.
+func (e *SaveURLError) Error() string {
+	if e.Tag == SaveURLErrorPath {
+		return fmt.Sprintf("dropbox: save_url failed: %s/%s", e.Tag, e.Path.Tag)
+	}
+	return fmt.Sprintf("dropbox: save_url failed: %s", e.Tag)
+}
+
+// SaveURLCheckJobStatusInput request input.
+type SaveURLCheckJobStatusInput struct {
+	AsyncJobID string `json:"async_job_id"`
+}
+
+// SaveURLCheckJobStatusOutput request output. Tag is one of AsyncJobInProgress,
+// AsyncJobComplete or AsyncJobFailed.
+type SaveURLCheckJobStatusOutput struct {
+	Tag      string        `json:".tag"`
+	Complete *FileMetadata `json:"-"`
+	Failed   *SaveURLError `json:"failed,omitempty"`
+}
+
+// UnmarshalJSON decodes the completed file metadata when present.
+func (o *SaveURLCheckJobStatusOutput) UnmarshalJSON(b []byte) error {
+	type output SaveURLCheckJobStatusOutput
+	if err := json.Unmarshal(b, (*output)(o)); err != nil {
+		return err
+	}
+
+	if o.Tag != AsyncJobComplete {
+		return nil
+	}
+
+	o.Complete = NewFileMetadata()
+	return json.Unmarshal(b, o.Complete)
+}
+
+// status implements jobStatus.
+func (o *SaveURLCheckJobStatusOutput) status() (string, error) {
+	if o.Failed == nil {
+		return o.Tag, &SaveURLError{}
+	}
+	return o.Tag, o.Failed
+}
+
+// SaveURLCheckJobStatus checks the status of a SaveURL job.
+func (c *Files) SaveURLCheckJobStatus(in *SaveURLCheckJobStatusInput) (out *SaveURLCheckJobStatusOutput, err error) {
+	body, err := c.call("/files/save_url/check_job_status", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// SaveURLAndWait saves the data from a URL to a file in Dropbox, polling the
+// job with backoff until it completes. A failed job returns a *SaveURLError
+// and a job which doesn't finish in time returns ErrJobTimeout.
+func (c *Files) SaveURLAndWait(in *SaveURLInput) (*FileMetadata, error) {
+	out, err := c.SaveURL(in)
+	if err != nil {
+		return nil, err
+	}
+
+	if out.Tag == AsyncJobComplete {
+		return out.Complete, nil
+	}
+
+	var file *FileMetadata
+	err = waitForJob(func() (jobStatus, error) {
+		status, err := c.SaveURLCheckJobStatus(&SaveURLCheckJobStatusInput{
+			AsyncJobID: out.AsyncJobID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		file = status.Complete
+		return status, nil
+	})
+
+	return file, err
+}
+
+// GetTemporaryLinkInput request input.
+type GetTemporaryLinkInput struct {
+	Path string `json:"path"`
+}
+
+// GetTemporaryLinkOutput request output.
+type GetTemporaryLinkOutput struct {
+	Metadata *FileMetadata `json:"metadata"`
+	Link     string        `json:"link"`
+}
+
+// GetTemporaryLink returns a URL to stream the contents of a file. The link
+// expires in four hours and can be used without authorization.
+func (c *Files) GetTemporaryLink(in *GetTemporaryLinkInput) (out *GetTemporaryLinkOutput, err error) {
+	body, err := c.call("/files/get_temporary_link", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// UploadToTemporaryLink uploads r to a link from GetTemporaryUploadLink the
+// same way a browser would, without an access token. When client is nil
+// http.DefaultClient is used.
+func UploadToTemporaryLink(client *http.Client, link string, r io.Reader) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequest("POST", link, r)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	c := &Client{Config: &Config{HTTPClient: client}}
+	body, _, err := c.do(req)
+	if err != nil {
+		return err
+	}
+
+	return body.Close()
+}
+
+// ThumbnailError describes why a thumbnail could not be generated.
+type ThumbnailError struct {
+	Tag  string `json:".tag"`
+	Path struct {
+		Tag string `json:".tag"`
+	} `json:"path"`
+}
+
+// Error string.
+func (e *ThumbnailError) Error() string {
+	if e.Tag == "path" {
+		return fmt.Sprintf("dropbox: thumbnail failed: %s/%s", e.Tag, e.Path.Tag)
+	}
+	return fmt.Sprintf("dropbox: thumbnail failed: %s", e.Tag)
+}
+
+// GetThumbnailBatchMaxEntries is the maximum number of entries per batch.
+const GetThumbnailBatchMaxEntries = 25
+
+// GetThumbnailBatchInput request input.
+type GetThumbnailBatchInput struct {
+	Entries []*GetThumbnailInput `json:"entries"` // max=25
+}
+
+// GetThumbnailBatchResultEntry is the result for a single entry. Tag is
+// either "success", with Metadata and Thumbnail set, or "failure".
+type GetThumbnailBatchResultEntry struct {
+	Tag       string          `json:".tag"`
+	Metadata  *FileMetadata   `json:"metadata,omitempty"`
+	Thumbnail []byte          `json:"thumbnail,omitempty"` // Decoded from base64.
+	Failure   *ThumbnailError `json:"failure,omitempty"`
+}
+
+// GetThumbnailBatchOutput request output.
+type GetThumbnailBatchOutput struct {
+	Entries []*GetThumbnailBatchResultEntry `json:"entries"`
+}
+
+// GetThumbnailBatch returns thumbnails for up to 25 files in one request.
+func (c *Files) GetThumbnailBatch(in *GetThumbnailBatchInput) (out *GetThumbnailBatchOutput, err error) {
+	body, err := c.callContent("/files/get_thumbnail_batch", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// thumbnailBatchConcurrency is the number of batches requested at once.
+const thumbnailBatchConcurrency = 4
+
+// GetThumbnails returns thumbnails for any number of paths, split into
+// concurrent batches. Format, size and mode are taken from opts, which
+// defaults to NewGetThumbnailInput when nil. Results are in input order.
+func (c *Files) GetThumbnails(paths []string, opts *GetThumbnailInput) ([]*GetThumbnailBatchResultEntry, error) {
+	if opts == nil {
+		opts = NewGetThumbnailInput()
+	}
+
+	results := make([]*GetThumbnailBatchResultEntry, len(paths))
+	errs := make(chan error, 1)
+	sem := make(chan struct{}, thumbnailBatchConcurrency)
+	var wg sync.WaitGroup
+
+	for start := 0; start < len(paths); start += GetThumbnailBatchMaxEntries {
+		end := start + GetThumbnailBatchMaxEntries
+		if end > len(paths) {
+			end = len(paths)
+		}
+
+		in := &GetThumbnailBatchInput{}
+		for _, p := range paths[start:end] {
+			in.Entries = append(in.Entries, &GetThumbnailInput{
+				Path:   p,
+				Format: opts.Format,
+				Size:   opts.Size,
+				Mode:   opts.Mode,
+			})
+		}
+
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(start int, in *GetThumbnailBatchInput) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			out, err := c.GetThumbnailBatch(in)
+			if err == nil && len(out.Entries) != len(in.Entries) {
+				err = fmt.Errorf("dropbox: thumbnail batch returned %d entries, expected %d", len(out.Entries), len(in.Entries))
+			}
+			if err != nil {
+				select {
+				case errs <- err:
+				default:
+				}
+				return
+			}
+
+			copy(results[start:], out.Entries)
+		}(start, in)
+	}
+
+	wg.Wait()
+
+	select {
+	case err := <-errs:
+		return nil, err
+	default:
+		return results, nil
+	}
+}
+
+// DownloadZipInput request input.
+type DownloadZipInput struct {
+	Path string `json:"path"`
+}
+
+// DownloadZipOutput request output.
+type DownloadZipOutput struct {
+	Body     io.ReadCloser   `json:"-"`
+	Length   int64           `json:"-"`
+	Metadata *FolderMetadata `json:"metadata"`
+}
+
+// DownloadZip a folder as a zip file. The folder must be less than 20 GB
+// in size and have fewer than 10,000 total files.
+func (c *Files) DownloadZip(in *DownloadZipInput) (out *DownloadZipOutput, err error) {
+	out = &DownloadZipOutput{}
+	body, l, err := c.downloadResult("/files/download_zip", in, out)
+	if err != nil {
+		return nil, err
+	}
+
+	out.Body = body
+	out.Length = l
+	return
+}
+
+// DownloadZipTo downloads a folder as a zip file and extracts it into dir.
+// Files which can't be downloaded, such as Paper docs, aren't part of the zip
+// and are exported in their default format next to the extracted files.
+func (c *Files) DownloadZipTo(in *DownloadZipInput, dir string) (*FolderMetadata, error) {
+	out, err := c.DownloadZip(in)
+	if err != nil {
+		return nil, err
+	}
+	defer out.Body.Close()
+
+	if err := ExtractZip(out.Body, dir); err != nil {
+		return nil, err
+	}
+
+	if err := c.exportFolder(out.Metadata, dir); err != nil {
+		return nil, err
+	}
+
+	return out.Metadata, nil
+}
+
+// exportFolder exports the non-downloadable files below folder into dir,
+// using the same layout as the folder's zip.
+func (c *Files) exportFolder(folder *FolderMetadata, dir string) error {
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	in := NewListFolderInput()
+	in.Path = folder.ID
+	in.Recursive = true
+
+	list, err := c.ListFolder(in)
+	if err != nil {
+		return err
+	}
+
+	for {
+		for _, e := range list.Entries {
+			file := e.File()
+			if file == nil || file.IsDownloadable {
+				continue
+			}
+
+			if err := c.exportFile(folder, file, root); err != nil {
+				return err
+			}
+		}
+
+		if !list.HasMore {
+			return nil
+		}
+
+		list, err = c.ListFolderContinue(&ListFolderContinueInput{
+			Cursor: list.Cursor,
+		})
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// exportFile exports file to its place below root, named after the export.
+func (c *Files) exportFile(folder *FolderMetadata, file *FileMetadata, root string) error {
+	out, err := c.Export(&ExportInput{Path: file.ID})
+	if err != nil {
+		return err
+	}
+	defer out.Body.Close()
+
+	path, err := localPath(root, pathpkg.Join(exportDir(folder, file), pathpkg.Base(out.ExportMetadata.Name)))
+	if err != nil {
+		return err
+	}
+
+	return writeLocalFile(path, out.Body)
+}
+
+// exportDir returns the directory of file within the zip of folder. Paths are
+// compared by component, since lowercasing may change their length in bytes.
+func exportDir(folder *FolderMetadata, file *FileMetadata) string {
+	if !strings.HasPrefix(file.PathLower, folder.PathLower+"/") {
+		return folder.Name
+	}
+
+	n := len(strings.Split(strings.Trim(folder.PathLower, "/"), "/"))
+	parts := strings.Split(strings.Trim(file.PathDisplay, "/"), "/")
+	if len(parts) <= n {
+		return folder.Name
+	}
+
+	return pathpkg.Join(append([]string{folder.Name}, parts[n:len(parts)-1]...)...)
+}
+
+// ExtractZip extracts the zip stream r into dir. The stream is buffered to a
+// temporary file since zip archives can't be read sequentially. Entries which
+// would be written outside of dir are rejected.
+func ExtractZip(r io.Reader, dir string) error {
+	tmp, err := ioutil.TempFile("", "dropbox-zip")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, r)
+	if err != nil {
+		return err
+	}
+
+	zr, err := zip.NewReader(tmp, size)
+	if err != nil {
+		return err
+	}
+
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range zr.File {
+		if err := extractZipFile(f, root); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// extractZipFile writes a single zip entry below root.
+func extractZipFile(f *zip.File, root string) error {
+	path, err := localPath(root, f.Name)
+	if err != nil {
+		return err
+	}
+
+	if f.FileInfo().IsDir() {
+		return os.MkdirAll(path, 0755)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return writeLocalFile(path, rc)
+}
+
+// localPath returns the local path of the slash separated name below root,
+// rejecting absolute names and names which would escape root.
+func localPath(root, name string) (string, error) {
+	local := filepath.FromSlash(name)
+	if filepath.IsAbs(local) || strings.HasPrefix(name, "/") || strings.HasPrefix(name, `\`) || filepath.VolumeName(local) != "" {
+		return "", fmt.Errorf("dropbox: entry %q has an absolute path", name)
+	}
+
+	path := filepath.Join(root, local)
+	if path != root && !strings.HasPrefix(path, root+string(filepath.Separator)) {
+		return "", fmt.Errorf("dropbox: entry %q is outside of %q", name, root)
+	}
+
+	return path, nil
+}
+
+// writeLocalFile writes r to path, creating parent directories as needed.
+func writeLocalFile(path string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	w, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(w, r); err != nil {
+		w.Close()
+		return err
+	}
+
+	return w.Close()
+}
+
+// ExportInput request input.
+type ExportInput struct {
+	Path         string `json:"path"`
+	ExportFormat string `json:"export_format,omitempty"` // One of FileMetadata.ExportFormats, defaults to ExportInfo.ExportAs.
+}
+
+// ExportMetadata specifies the exported file.
+type ExportMetadata struct {
+	Name          string `json:"name"`
+	Size          uint64 `json:"size"`
+	ExportHash    string `json:"export_hash,omitempty"`
+	PaperRevision int64  `json:"paper_revision,omitempty"`
+}
+
+// ExportOutput request output.
+type ExportOutput struct {
+	Body           io.ReadCloser   `json:"-"`
+	Length         int64           `json:"-"`
+	ExportMetadata *ExportMetadata `json:"export_metadata"`
+	FileMetadata   *FileMetadata   `json:"file_metadata"`
+}
+
+// Export a file that can't be downloaded directly, such as a Paper doc or
+// Google Doc, in one of its export formats.
+func (c *Files) Export(in *ExportInput) (out *ExportOutput, err error) {
+	out = &ExportOutput{}
+	body, l, err := c.downloadResult("/files/export", in, out)
+	if err != nil {
+		return nil, err
+	}
+
+	out.Body = body
+	out.Length = l
+	return
+}
+
+// DownloadOrExport downloads a file, or exports it in format when it isn't
+// downloadable. An empty format uses the file's default export format.
+func (c *Files) DownloadOrExport(file *FileMetadata, format string) (*DownloadOutput, error) {
+	path := file.PathLower
+	if file.ID != "" {
+		path = file.ID
+	}
+
+	if file.IsDownloadable {
+		return c.Download(&DownloadInput{Path: path})
+	}
+
+	out, err := c.Export(&ExportInput{
+		Path:         path,
+		ExportFormat: format,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &DownloadOutput{out.Body, out.Length, out.FileMetadata}, nil
+}
+
+// FileLockContent specifies who holds a lock on a file.
+type FileLockContent struct {
+	Tag                 string    `json:".tag"` // "single_user" or "unlocked".
+	Created             time.Time `json:"created,omitempty"`
+	LockHolderAccountID string    `json:"lock_holder_account_id,omitempty"`
+	LockHolderTeamID    string    `json:"lock_holder_team_id,omitempty"`
+}
+
+// FileLock specifies a lock on a file.
+type FileLock struct {
+	Content FileLockContent `json:"content"`
+}
+
+// LockFileError describes why a file could not be locked or unlocked.
+// Lock is set for "lock_conflict" errors.
+type LockFileError struct {
+	Tag        string    `json:".tag"`
+	Lock       *FileLock `json:"lock,omitempty"`
+	PathLookup struct {
+		Tag string `json:".tag"`
+	} `json:"path_lookup"`
+}
+
+// Error string.
+func (e *LockFileError) Error() string {
+	switch {
+	case e.Tag == "lock_conflict" && e.Lock != nil:
+		return fmt.Sprintf("dropbox: file is locked by %s", e.Lock.Content.LockHolderAccountID)
+	case e.Tag == "path_lookup":
+		return fmt.Sprintf("dropbox: lock failed: %s/%s", e.Tag, e.PathLookup.Tag)
+	}
+	return fmt.Sprintf("dropbox: lock failed: %s", e.Tag)
+}
+
+// LockFileArg specifies a file to lock, unlock or query.
+type LockFileArg struct {
+	Path string `json:"path"`
+}
+
+// LockFileResultEntry is the result for a single entry. Tag is either
+// "success", with Metadata and Lock set, or "failure".
+type LockFileResultEntry struct {
+	Tag      string         `json:".tag"`
+	Metadata *Metadata      `json:"metadata,omitempty"`
+	Lock     *FileLock      `json:"lock,omitempty"`
+	Failure  *LockFileError `json:"failure,omitempty"`
+}
+
+// LockFileBatchOutput request output.
+type LockFileBatchOutput struct {
+	Entries []*LockFileResultEntry `json:"entries"`
+}
+
+// LockFileBatchInput request input.
+type LockFileBatchInput struct {
+	Entries []*LockFileArg `json:"entries"`
+}
+
+// LockFileBatch locks the files, returning a result per entry.
+func (c *Files) LockFileBatch(in *LockFileBatchInput) (out *LockFileBatchOutput, err error) {
+	body, err := c.call("/files/lock_file_batch", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// UnlockFileBatchInput request input.
+type UnlockFileBatchInput struct {
+	Entries []*LockFileArg `json:"entries"`
+}
+
+// UnlockFileBatch unlocks the files, returning a result per entry.
+func (c *Files) UnlockFileBatch(in *UnlockFileBatchInput) (out *LockFileBatchOutput, err error) {
+	body, err := c.call("/files/unlock_file_batch", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// GetFileLockBatchInput request input.
+type GetFileLockBatchInput struct {
+	Entries []*LockFileArg `json:"entries"`
+}
+
+// GetFileLockBatch returns the lock state of the files.
+func (c *Files) GetFileLockBatch(in *GetFileLockBatchInput) (out *LockFileBatchOutput, err error) {
+	body, err := c.call("/files/get_file_lock_batch", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// lockResult returns the single entry of a batch, or its failure.
+func lockResult(out *LockFileBatchOutput) (*LockFileResultEntry, error) {
+	if len(out.Entries) != 1 {
+		return nil, fmt.Errorf("dropbox: lock batch returned %d entries, expected 1", len(out.Entries))
+	}
+
+	entry := out.Entries[0]
+	if entry.Failure != nil {
+		return nil, entry.Failure
+	}
+
+	return entry, nil
+}
+
+// WithLock locks the file at path, calls fn and always unlocks it again. When
+// another user holds the lock a *LockFileError is returned and fn isn't called.
+func (c *Files) WithLock(path string, fn func() error) (err error) {
+	out, err := c.LockFileBatch(&LockFileBatchInput{
+		Entries: []*LockFileArg{{Path: path}},
+	})
+	if err != nil {
+		return
+	}
+
+	if _, err = lockResult(out); err != nil {
+		return
+	}
+
+	defer func() {
+		out, uerr := c.UnlockFileBatch(&UnlockFileBatchInput{
+			Entries: []*LockFileArg{{Path: path}},
+		})
+		if uerr == nil {
+			_, uerr = lockResult(out)
+		}
+		if err == nil {
+			err = uerr
+		}
+	}()
+
+	return fn()
+}
+
+// TagMaxLength is the maximum length of a tag.
+const TagMaxLength = 32
+
+// tagPattern matches valid tag text.
+var tagPattern = regexp.MustCompile(`^\w+$`)
+
+// ValidateTag checks tag against Dropbox's tag naming rules: 1 to 32 letters,
+// digits or underscores.
+func ValidateTag(tag string) error {
+	if len(tag) == 0 || len(tag) > TagMaxLength {
+		return fmt.Errorf("dropbox: tag %q must be 1 to %d characters", tag, TagMaxLength)
+	}
+
+	if !tagPattern.MatchString(tag) {
+		return fmt.Errorf("dropbox: tag %q may only contain letters, digits and underscores", tag)
+	}
+
+	return nil
+}
+
+// AddTagInput request input.
+type AddTagInput struct {
+	Path    string `json:"path"`
+	TagText string `json:"tag_text"`
+}
+
+// AddTag adds a tag to a file or folder.
+func (c *Files) AddTag(in *AddTagInput) (err error) {
+	if err = ValidateTag(in.TagText); err != nil {
+		return
+	}
+
+	body, err := c.call("/files/tags/add", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	return
+}
+
+// RemoveTagInput request input.
+type RemoveTagInput struct {
+	Path    string `json:"path"`
+	TagText string `json:"tag_text"`
+}
+
+// RemoveTag removes a tag from a file or folder.
+func (c *Files) RemoveTag(in *RemoveTagInput) (err error) {
+	if err = ValidateTag(in.TagText); err != nil {
+		return
+	}
+
+	body, err := c.call("/files/tags/remove", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	return
+}
+
+// Tag on a file or folder.
+type Tag struct {
+	Tag     string `json:".tag"` // "user_generated_tag".
+	TagText string `json:"tag_text"`
+}
+
+// PathToTags specifies the tags of a path.
+type PathToTags struct {
+	Path string `json:"path"`
+	Tags []*Tag `json:"tags"`
+}
+
+// HasTag returns true if the path is tagged with tag.
+func (p *PathToTags) HasTag(tag string) bool {
+	for _, t := range p.Tags {
+		if strings.EqualFold(t.TagText, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetTagsMaxPaths is the maximum number of paths FilterByTag sends per
+// GetTags request.
+const GetTagsMaxPaths = 100
+
+// GetTagsInput request input.
+type GetTagsInput struct {
+	Paths []string `json:"paths"`
+}
+
+// GetTagsOutput request output.
+type GetTagsOutput struct {
+	PathsToTags []*PathToTags `json:"paths_to_tags"`
+}
+
+// GetTags returns the tags of many files or folders.
+func (c *Files) GetTags(in *GetTagsInput) (out *GetTagsOutput, err error) {
+	body, err := c.call("/files/tags/get", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// FilterByTag returns the search matches tagged with tag, in their original
+// order. Tags are fetched in batches of GetTagsMaxPaths and matches without
+// metadata are skipped.
+func (c *Files) FilterByTag(matches []*SearchMatchV2, tag string) ([]*SearchMatchV2, error) {
+	if err := ValidateTag(tag); err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	var found []*SearchMatchV2
+	for _, m := range matches {
+		if m == nil || m.Metadata == nil {
+			continue
+		}
+		paths = append(paths, m.Metadata.Base().PathLower)
+		found = append(found, m)
+	}
+
+	tagged := make(map[string]bool)
+	for start := 0; start < len(paths); start += GetTagsMaxPaths {
+		end := start + GetTagsMaxPaths
+		if end > len(paths) {
+			end = len(paths)
+		}
+
+		out, err := c.GetTags(&GetTagsInput{Paths: paths[start:end]})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, p := range out.PathsToTags {
+			if p.HasTag(tag) {
+				tagged[strings.ToLower(p.Path)] = true
+			}
+		}
+	}
+
+	var filtered []*SearchMatchV2
+	for i, m := range found {
+		if tagged[paths[i]] {
+			filtered = append(filtered, m)
+		}
+	}
+
+	return filtered, nil
+}
+
+// restoreConcurrency is the number of files restored at once.
+const restoreConcurrency = 8
+
+// RestoreResult is the outcome of restoring a single deleted file.
+type RestoreResult struct {
+	Path     string
+	Rev      string
+	Metadata *FileMetadata
+	Err      error
+}
+
+// RestoreFolderReport lists the restored and failed files of RestoreFolder.
+type RestoreFolderReport struct {
+	Restored []*RestoreResult
+	Failed   []*RestoreResult
+}
+
+// RestoreFolder restores every deleted file below the folder path to its
+// latest revision. The folder itself may be deleted, in which case it's
+// listed through its nearest existing ancestor. Deleted subfolders are
+// recreated implicitly by restoring their files. Per-file failures are
+// reported rather than returned as an error.
+func (c *Files) RestoreFolder(path string) (*RestoreFolderReport, error) {
+	dir, within, err := c.restoreRoot(path)
+	if err != nil {
+		return nil, err
+	}
+
+	in := NewListFolderInput()
+	in.Path = dir
+	in.Recursive = true
+	in.IncludeDeleted = true
+
+	out, err := c.ListFolder(in)
+	if err != nil {
+		return nil, err
+	}
+
+	var deleted []string
+	for {
+		for _, e := range out.Entries {
+			p := e.Base().PathLower
+			if e.IsDeleted() && (within == "" || p == within || strings.HasPrefix(p, within+"/")) {
+				deleted = append(deleted, p)
+			}
+		}
+
+		if !out.HasMore {
+			break
+		}
+
+		out, err = c.ListFolderContinue(&ListFolderContinueInput{
+			Cursor: out.Cursor,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]*RestoreResult, len(deleted))
+	sem := make(chan struct{}, restoreConcurrency)
+	var wg sync.WaitGroup
+
+	for i, p := range deleted {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, p string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i] = c.restoreLatest(p)
+		}(i, p)
+	}
+
+	wg.Wait()
+
+	report := &RestoreFolderReport{}
+	for _, r := range results {
+		switch {
+		case r == nil:
+		case r.Err != nil:
+			report.Failed = append(report.Failed, r)
+		default:
+			report.Restored = append(report.Restored, r)
+		}
+	}
+
+	return report, nil
+}
+
+// restoreRoot returns the folder to list when restoring path, and the lower
+// case path which restored entries must be within. A deleted path is listed
+// through its nearest existing ancestor.
+func (c *Files) restoreRoot(path string) (dir, within string, err error) {
+	if normalizePath(path) == "" {
+		return "", "", nil
+	}
+
+	meta, err := c.GetMetadata(&GetMetadataInput{
+		Path:           path,
+		IncludeDeleted: true,
+	})
+	if err != nil {
+		return
+	}
+
+	within = meta.Base().PathLower
+	if !meta.IsDeleted() {
+		return path, within, nil
+	}
+
+	for dir = pathpkg.Dir(within); dir != "/"; dir = pathpkg.Dir(dir) {
+		meta, err = c.GetMetadata(&GetMetadataInput{
+			Path:           dir,
+			IncludeDeleted: true,
+		})
+		if err != nil {
+			return
+		}
+
+		if !meta.IsDeleted() {
+			return dir, within, nil
+		}
+	}
+
+	return "", within, nil
+}
+
+// restoreLatest restores the deleted file at path to its latest revision,
+// returning nil when path was a folder.
+func (c *Files) restoreLatest(path string) *RestoreResult {
+	r := &RestoreResult{Path: path}
+
+	revs, err := c.ListRevisions(&ListRevisionsInput{
+		Path:  path,
+		Mode:  ListRevisionsModePath,
+		Limit: 1,
+	})
+	if isNotFile(err) {
+		return nil
+	}
+	if err != nil {
+		r.Err = err
+		return r
+	}
+
+	if len(revs.Entries) == 0 {
+		r.Err = fmt.Errorf("dropbox: no revisions of %q to restore", path)
+		return r
+	}
+
+	r.Rev = revs.Entries[0].Rev
+
+	out, err := c.Restore(&RestoreInput{
+		Path: path,
+		Rev:  r.Rev,
+	})
+	if err != nil {
+		r.Err = err
+		return r
+	}
+
+	r.Metadata = &out.FileMetadata
+	return r
+}
+
+// isNotFile returns true if err is a path/not_file lookup error.
+func isNotFile(err error) bool {
+	e, ok := err.(*Error)
+	if !ok || e.Tag != "path" {
+		return false
+	}
+
+	var details struct {
+		Path struct {
+			Tag string `json:".tag"`
+		} `json:"path"`
+	}
+	return e.decodeDetails(&details) == nil && details.Path.Tag == "not_file"
+}
+
+// UpdateMaxRetries is the number of times Update retries after a conflict.
+const UpdateMaxRetries = 3
+
+// isConflict returns true if err is a write conflict.
+func isConflict(err error) bool {
+	e, ok := err.(*Error)
+	return ok && strings.HasPrefix(e.Summary, "path/conflict")
+}
+
+// Update performs a read-modify-write cycle on the file at path. The current
+// contents are passed to fn and its result is uploaded in update mode against
+// the revision that was read, with StrictConflict set. When another writer
+// changed the file in the meantime the cycle is retried up to UpdateMaxRetries
+// times before the conflict error is returned.
+func (c *Files) Update(path string, fn func(current io.Reader) (io.Reader, error)) (*FileMetadata, error) {
+	var err error
+	for i := 0; i <= UpdateMaxRetries; i++ {
+		var file *FileMetadata
+		file, err = c.update(path, fn)
+		if err == nil {
+			return file, nil
+		}
+
+		if !isConflict(err) {
+			return nil, err
+		}
+	}
+
+	return nil, err
+}
+
+// update performs a single read-modify-write cycle.
+func (c *Files) update(path string, fn func(current io.Reader) (io.Reader, error)) (*FileMetadata, error) {
+	current, err := c.Download(&DownloadInput{Path: path})
+	if err != nil {
+		return nil, err
+	}
+	defer current.Body.Close()
+
+	r, err := fn(current.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	in := &UploadInput{
+		CommitInfo: CommitInfo{
+			Path:           path,
+			StrictConflict: true,
+		},
+		Reader: r,
+	}
+	in.SetMode(WriteModeUpdate, current.Metadata.Rev)
+
+	out, err := c.Upload(in)
+	if err != nil {
+		return nil, err
+	}
+
+	return &out.FileMetadata, nil
+}
@@ -1,9 +1,16 @@
 package dropbox
 
 import (
+	"archive/zip"
 	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"net/http"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
@@ -19,9 +26,11 @@ func TestFiles_Upload(t *testing.T) {
 	defer file.Close()
 
 	out, err := c.Files.Upload(&UploadInput{
-		Mute:   true,
-		Mode:   WriteModeOverwrite,
-		Path:   "/Readme.md",
+		CommitInfo: CommitInfo{
+			Mute: true,
+			Mode: WriteModeOverwrite,
+			Path: "/Readme.md",
+		},
 		Reader: file,
 	})
 
@@ -57,7 +66,39 @@ func TestFiles_GetMetadata(t *testing.T) {
 		Path: "/Readme.md",
 	})
 	assert.NoError(t, err)
-	assert.Equal(t, "file", out.Tag)
+	assert.Equal(t, "file", out.Tag())
+	assert.True(t, out.IsFile())
+}
+
+func TestMetadata_UnmarshalJSON(t *testing.T) {
+	var out ListFolderOutput
+	err := json.Unmarshal([]byte(`{"entries": [
+		{".tag": "file", "name": "a.txt", "path_lower": "/a.txt", "rev": "1", "size": 3,
+		 "file_lock_info": {"is_lockholder": true, "lockholder_name": "Ann"}, "preview_url": "https://example.com/a"},
+		{".tag": "folder", "name": "b", "path_lower": "/b", "id": "id:b",
+		 "sharing_info": {"read_only": false, "shared_folder_id": "84528192421", "traverse_only": true, "no_access": false}},
+		{".tag": "deleted", "name": "c", "path_lower": "/c"}
+	]}`), &out)
+	assert.NoError(t, err)
+	assert.Len(t, out.Entries, 3)
+
+	file := out.Entries[0]
+	assert.True(t, file.IsFile())
+	assert.Equal(t, "/a.txt", file.Base().PathLower)
+	assert.True(t, file.File().IsDownloadable)
+	assert.True(t, file.File().FileLockInfo.IsLockholder)
+	assert.Equal(t, "https://example.com/a", file.File().PreviewURL)
+
+	folder := out.Entries[1]
+	assert.True(t, folder.IsFolder())
+	assert.Nil(t, folder.File())
+	assert.Equal(t, "84528192421", folder.Folder().SharingInfo.SharedFolderID)
+	assert.True(t, folder.Folder().SharingInfo.TraverseOnly)
+
+	deleted := out.Entries[2]
+	assert.True(t, deleted.IsDeleted())
+	assert.Equal(t, "deleted", deleted.Tag())
+	assert.Equal(t, "c", deleted.Base().Name)
 }
 
 func TestFiles_ListFolder(t *testing.T) {
@@ -87,15 +128,65 @@ func TestFiles_ListFolder_root(t *testing.T) {
 func TestFiles_Search(t *testing.T) {
 	c := client()
 
+	opts := NewSearchOptions()
+	opts.Path = "/"
+
 	out, err := c.Files.Search(&SearchInput{
-		Path:  "/",
-		Query: "hello",
+		Options: opts,
+		Query:   "hello",
 	})
 
 	assert.NoError(t, err)
 	assert.Equal(t, 2, len(out.Matches))
 }
 
+func TestFiles_SearchIter(t *testing.T) {
+	c := client()
+
+	opts := NewSearchOptions()
+	opts.MaxResults = 1
+	opts.OrderBy = SearchOrderByLastModifiedTime
+
+	it := c.Files.SearchIter(&SearchInput{
+		Options: opts,
+		Query:   "hello",
+	}, 2)
+
+	var names []string
+	for it.Next() {
+		m := it.Match()
+		assert.Equal(t, SearchMatchTypeFilename, m.MatchType.Tag)
+		names = append(names, m.Metadata.Base().Name)
+	}
+
+	assert.NoError(t, it.Err())
+	assert.Len(t, names, 2)
+}
+
+func TestSearchOutput_UnmarshalJSON(t *testing.T) {
+	var out SearchOutput
+	err := json.Unmarshal([]byte(`{"matches": [{
+		"metadata": {".tag": "metadata", "metadata": {".tag": "file", "name": "hello.txt", "path_lower": "/hello.txt"}},
+		"match_type": {".tag": "filename"}
+	}]}`), &out)
+	assert.NoError(t, err)
+	assert.Len(t, out.Matches, 1)
+
+	m := out.Matches[0]
+	assert.True(t, m.Metadata.IsFile())
+	assert.Equal(t, "/hello.txt", m.Metadata.Base().PathLower)
+	assert.Equal(t, SearchMatchTypeFilename, m.MatchType.Tag)
+
+	b, err := json.Marshal(m)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"metadata":{".tag":"metadata","metadata":{".tag":"file"`)
+
+	var wrapped Metadata
+	err = json.Unmarshal([]byte(`{".tag": "metadata", "metadata": {".tag": "file"}}`), &wrapped)
+	assert.NoError(t, err)
+	assert.Nil(t, wrapped.Entry, "only search matches unwrap the metadata union")
+}
+
 func TestFiles_Delete(t *testing.T) {
 	c := client()
 
@@ -104,7 +195,7 @@ func TestFiles_Delete(t *testing.T) {
 	})
 
 	assert.NoError(t, err)
-	assert.Equal(t, "/readme.md", out.PathLower)
+	assert.Equal(t, "/readme.md", out.Metadata.Base().PathLower)
 }
 
 // A gray, 64 by 64 px PNG
@@ -134,14 +225,20 @@ func TestFiles_GetThumbnail(t *testing.T) {
 	{
 		buf := bytes.NewBuffer(grayPng)
 		_, err := c.Files.Upload(&UploadInput{
-			Mute:   true,
-			Mode:   WriteModeOverwrite,
-			Path:   "/gray.png",
+			CommitInfo: CommitInfo{
+				Mute: true,
+				Mode: WriteModeOverwrite,
+				Path: "/gray.png",
+			},
 			Reader: buf,
 		})
 		assert.NoError(t, err, "error uploading file")
 	}
-	out, err := c.Files.GetThumbnail(&GetThumbnailInput{"/gray.png", GetThumbnailFormatJPEG, GetThumbnailSizeW32H32})
+	out, err := c.Files.GetThumbnail(&GetThumbnailInput{
+		Path:   "/gray.png",
+		Format: ThumbnailFormatJPEG,
+		Size:   ThumbnailSizeW32H32,
+	})
 	assert.NoError(t, err)
 	if err != nil {
 		return
@@ -194,3 +291,416 @@ func TestFiles_ContentHash(t *testing.T) {
 
 	assert.Equal(t, "485291fa0ee50c016982abbfa943957bcd231aae0492ccbaa22c58e3997b35e0", hash)
 }
+
+func TestMetadata_MarshalJSON(t *testing.T) {
+	var in Metadata
+	err := json.Unmarshal([]byte(`{".tag": "folder", "name": "b", "path_lower": "/b", "id": "id:b",
+		"sharing_info": {"read_only": true, "traverse_only": false, "no_access": false}}`), &in)
+	assert.NoError(t, err)
+
+	b, err := json.Marshal(in)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `".tag":"folder"`)
+
+	var out Metadata
+	assert.NoError(t, json.Unmarshal(b, &out))
+	assert.True(t, out.IsFolder())
+	assert.Equal(t, in.Folder(), out.Folder())
+}
+
+func TestMetadata_UnmarshalJSON_unknown(t *testing.T) {
+	var out ListFolderOutput
+	err := json.Unmarshal([]byte(`{"entries": [
+		{".tag": "file", "name": "a.txt", "path_lower": "/a.txt"},
+		{".tag": "shortcut", "name": "s", "path_lower": "/s"}
+	]}`), &out)
+	assert.NoError(t, err)
+	assert.Len(t, out.Entries, 2)
+
+	unknown := out.Entries[1]
+	assert.Nil(t, unknown.Entry)
+	assert.False(t, unknown.IsFile())
+	assert.Equal(t, "shortcut", unknown.Tag())
+	assert.Equal(t, "/s", unknown.Base().PathLower)
+
+	b, err := json.Marshal(unknown)
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"shortcut"`)
+}
+
+func TestFiles_SaveURLAndWait(t *testing.T) {
+	c := client()
+
+	out, err := c.Files.SaveURLAndWait(&SaveURLInput{
+		Path: "/milky-way.jpg",
+		URL:  "https://www.dropbox.com/static/images/developers/milky-way-nasa.jpg",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "/milky-way.jpg", out.PathLower)
+}
+
+func TestSaveURLCheckJobStatusOutput_UnmarshalJSON(t *testing.T) {
+	var out SaveURLCheckJobStatusOutput
+	err := json.Unmarshal([]byte(`{".tag": "failed", "failed": {".tag": "path", "path": {".tag": "conflict"}}}`), &out)
+	assert.NoError(t, err)
+	assert.Nil(t, out.Complete)
+	assert.EqualError(t, out.Failed, "dropbox: save_url failed: path/conflict")
+
+	err = json.Unmarshal([]byte(`{".tag": "complete", "name": "a.jpg", "path_lower": "/a.jpg", "size": 10}`), &out)
+	assert.NoError(t, err)
+	assert.Equal(t, "/a.jpg", out.Complete.PathLower)
+	assert.Equal(t, uint64(10), out.Complete.Size)
+}
+
+func TestFiles_GetTemporaryLink(t *testing.T) {
+	c := client()
+
+	in := NewGetTemporaryUploadLinkInput()
+	in.CommitInfo.Path = "/temporary.txt"
+	in.CommitInfo.SetMode(WriteModeOverwrite, "")
+
+	upload, err := c.Files.GetTemporaryUploadLink(in)
+	assert.NoError(t, err)
+
+	err = UploadToTemporaryLink(nil, upload.Link, bytes.NewBufferString("hello"))
+	assert.NoError(t, err)
+
+	out, err := c.Files.GetTemporaryLink(&GetTemporaryLinkInput{
+		Path: "/temporary.txt",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "/temporary.txt", out.Metadata.PathLower)
+
+	data, err := dry.FileGetBytes(out.Link, time.Second*5)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+}
+
+func TestFiles_GetThumbnails(t *testing.T) {
+	c := client()
+
+	out, err := c.Files.GetThumbnails([]string{"/gray.png", "/nothing.png"}, nil)
+	assert.NoError(t, err)
+	assert.Len(t, out, 2)
+
+	assert.Equal(t, "success", out[0].Tag)
+	assert.Equal(t, "/gray.png", out[0].Metadata.PathLower)
+	assert.Equal(t, []byte{0xff, 0xd8}, out[0].Thumbnail[:2], "should have jpeg header")
+
+	assert.Equal(t, "failure", out[1].Tag)
+	assert.Equal(t, "path", out[1].Failure.Tag)
+}
+
+func TestFiles_GetThumbnailV2(t *testing.T) {
+	c := client()
+
+	in := NewGetThumbnailV2Input(NewThumbnailV2PathResource("/gray.png"))
+	in.Format = ThumbnailFormatPNG
+
+	out, err := c.Files.GetThumbnailV2(in)
+	assert.NoError(t, err)
+	if err != nil {
+		return
+	}
+	defer out.Body.Close()
+
+	assert.Equal(t, "image/png", out.ContentType)
+	assert.Equal(t, "/gray.png", out.FileMetadata.PathLower)
+	assert.Nil(t, out.LinkMetadata)
+
+	buf := make([]byte, 4)
+	_, err = out.Body.Read(buf)
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0x89, 0x50, 0x4e, 0x47}, buf, "should have png header")
+}
+
+func TestFiles_DownloadZipTo(t *testing.T) {
+	c := client()
+
+	dir, err := ioutil.TempDir("", "dropbox-zip-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	out, err := c.Files.DownloadZipTo(&DownloadZipInput{Path: "/list"}, dir)
+	assert.NoError(t, err)
+	assert.Equal(t, "/list", out.PathLower)
+
+	_, err = os.Stat(filepath.Join(dir, "list"))
+	assert.NoError(t, err, "folder should be extracted")
+}
+
+func TestExportDir(t *testing.T) {
+	folder := &FolderMetadata{}
+	folder.Name = "İstanbul"
+	folder.PathLower = strings.ToLower("/Docs/İstanbul")
+
+	file := &FileMetadata{}
+	file.PathDisplay = "/Docs/İstanbul/Notes/plan.paper"
+	file.PathLower = strings.ToLower(file.PathDisplay)
+	assert.Equal(t, "İstanbul/Notes", exportDir(folder, file))
+
+	file.PathDisplay = "/Docs/İstanbul/plan.paper"
+	file.PathLower = strings.ToLower(file.PathDisplay)
+	assert.Equal(t, "İstanbul", exportDir(folder, file))
+}
+
+func TestFiles_DownloadOrExport(t *testing.T) {
+	c := client()
+
+	meta, err := c.Files.GetMetadata(&GetMetadataInput{
+		Path: "/sample.paper",
+	})
+	assert.NoError(t, err)
+
+	file := meta.File()
+	assert.False(t, file.IsDownloadable)
+	assert.Contains(t, file.ExportFormats(), "markdown")
+
+	out, err := c.Files.DownloadOrExport(file, "markdown")
+	assert.NoError(t, err)
+	defer out.Body.Close()
+
+	assert.NotEmpty(t, out.Length, "length should not be 0")
+}
+
+func TestFiles_WithLock(t *testing.T) {
+	c := client()
+
+	err := c.Files.WithLock("/hello.txt", func() error {
+		out, err := c.Files.GetFileLockBatch(&GetFileLockBatchInput{
+			Entries: []*LockFileArg{{Path: "/hello.txt"}},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "success", out.Entries[0].Tag)
+		assert.Equal(t, "single_user", out.Entries[0].Lock.Content.Tag)
+		assert.True(t, out.Entries[0].Metadata.File().FileLockInfo.IsLockholder)
+		return nil
+	})
+
+	assert.NoError(t, err)
+}
+
+func TestLockFileBatchOutput_UnmarshalJSON(t *testing.T) {
+	var out LockFileBatchOutput
+	err := json.Unmarshal([]byte(`{"entries": [{".tag": "failure", "failure": {".tag": "lock_conflict", "lock": {"content": {".tag": "single_user", "lock_holder_account_id": "dbid:abc"}}}}]}`), &out)
+	assert.NoError(t, err)
+
+	_, err = lockResult(&out)
+	assert.EqualError(t, err, "dropbox: file is locked by dbid:abc")
+
+	lerr, ok := err.(*LockFileError)
+	assert.True(t, ok)
+	assert.Equal(t, "single_user", lerr.Lock.Content.Tag)
+}
+
+func TestFiles_Tags(t *testing.T) {
+	c := client()
+
+	err := c.Files.AddTag(&AddTagInput{Path: "/hello.txt", TagText: "greeting"})
+	assert.NoError(t, err)
+
+	out, err := c.Files.GetTags(&GetTagsInput{Paths: []string{"/hello.txt"}})
+	assert.NoError(t, err)
+	assert.True(t, out.PathsToTags[0].HasTag("greeting"))
+
+	err = c.Files.RemoveTag(&RemoveTagInput{Path: "/hello.txt", TagText: "greeting"})
+	assert.NoError(t, err)
+}
+
+// roundTripFunc serves requests without a network.
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func TestFiles_FilterByTag_batches(t *testing.T) {
+	requests := 0
+	config := NewConfig("token")
+	config.HTTPClient = &http.Client{Transport: roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		requests++
+
+		var in GetTagsInput
+		if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+			return nil, err
+		}
+		assert.True(t, len(in.Paths) <= GetTagsMaxPaths)
+
+		out := &GetTagsOutput{}
+		for _, p := range in.Paths {
+			pt := &PathToTags{Path: p}
+			if p == "/f0" || p == "/f200" {
+				pt.Tags = append(pt.Tags, &Tag{Tag: "user_generated_tag", TagText: "greeting"})
+			}
+			out.PathsToTags = append(out.PathsToTags, pt)
+		}
+
+		b, _ := json.Marshal(out)
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Header:     http.Header{"Content-Type": {"application/json"}},
+			Body:       ioutil.NopCloser(bytes.NewReader(b)),
+		}, nil
+	})}
+
+	var matches []*SearchMatchV2
+	for i := 0; i < 250; i++ {
+		m := &SearchMatchV2{}
+		m.Metadata = &Metadata{Entry: &FileMetadata{BaseMetadata: BaseMetadata{PathLower: fmt.Sprintf("/f%d", i)}}}
+		matches = append(matches, m)
+	}
+	matches = append(matches, &SearchMatchV2{})
+
+	out, err := NewFiles(config).FilterByTag(matches, "greeting")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, []*SearchMatchV2{matches[0], matches[200]}, out)
+}
+
+func TestValidateTag(t *testing.T) {
+	assert.NoError(t, ValidateTag("project_x2"))
+	assert.Error(t, ValidateTag(""))
+	assert.Error(t, ValidateTag("has space"))
+	assert.Error(t, ValidateTag("#hash"))
+	assert.Error(t, ValidateTag(strings.Repeat("a", 33)))
+}
+
+func TestFiles_ListRevisionsIter(t *testing.T) {
+	c := client()
+
+	in := NewListRevisionsInput()
+	in.Path = "/sample.ppt"
+	in.Limit = 1
+
+	it := c.Files.ListRevisionsIter(in)
+
+	var revs []string
+	for it.Next() {
+		revs = append(revs, it.Revision().Rev)
+	}
+	assert.NoError(t, it.Err())
+	assert.NotEmpty(t, revs)
+
+	out, err := c.Files.DownloadRevision(revs[len(revs)-1])
+	assert.NoError(t, err)
+	defer out.Body.Close()
+
+	assert.NotEmpty(t, out.Length, "length should not be 0")
+}
+
+func TestFiles_RestoreFolder(t *testing.T) {
+	c := client()
+
+	_, err := c.Files.Upload(&UploadInput{
+		CommitInfo: CommitInfo{
+			Mute: true,
+			Mode: WriteModeOverwrite,
+			Path: "/restore/nested/hello.txt",
+		},
+		Reader: bytes.NewBufferString("hello"),
+	})
+	assert.NoError(t, err)
+
+	_, err = c.Files.Delete(&DeleteInput{Path: "/restore/nested"})
+	assert.NoError(t, err)
+
+	out, err := c.Files.RestoreFolder("/restore")
+	assert.NoError(t, err)
+	assert.Empty(t, out.Failed)
+	assert.Len(t, out.Restored, 1)
+	assert.Equal(t, "/restore/nested/hello.txt", out.Restored[0].Metadata.PathLower)
+}
+
+func TestFiles_RestoreFolder_deleted(t *testing.T) {
+	c := client()
+
+	for _, p := range []string{"/restore-deleted/tree/hello.txt", "/restore-deleted/sibling.txt"} {
+		_, err := c.Files.Upload(&UploadInput{
+			CommitInfo: CommitInfo{
+				Mute: true,
+				Mode: WriteModeOverwrite,
+				Path: p,
+			},
+			Reader: bytes.NewBufferString("hello"),
+		})
+		assert.NoError(t, err)
+	}
+
+	_, err := c.Files.Delete(&DeleteInput{Path: "/restore-deleted/tree"})
+	assert.NoError(t, err)
+
+	_, err = c.Files.Delete(&DeleteInput{Path: "/restore-deleted/sibling.txt"})
+	assert.NoError(t, err)
+
+	out, err := c.Files.RestoreFolder("/restore-deleted/tree")
+	assert.NoError(t, err)
+	assert.Empty(t, out.Failed)
+	assert.Len(t, out.Restored, 1, "deleted siblings shouldn't be restored")
+	assert.Equal(t, "/restore-deleted/tree/hello.txt", out.Restored[0].Metadata.PathLower)
+}
+
+func TestFiles_Update(t *testing.T) {
+	c := client()
+
+	_, err := c.Files.Upload(&UploadInput{
+		CommitInfo: CommitInfo{
+			Mute: true,
+			Mode: WriteModeOverwrite,
+			Path: "/counter.txt",
+		},
+		Reader: bytes.NewBufferString("hello"),
+	})
+	assert.NoError(t, err)
+
+	out, err := c.Files.Update("/counter.txt", func(current io.Reader) (io.Reader, error) {
+		b, err := ioutil.ReadAll(current)
+		if err != nil {
+			return nil, err
+		}
+		return bytes.NewBufferString(string(b) + " world"), nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(11), out.Size)
+}
+
+// zipOf returns an in-memory zip archive with the given entry names.
+func zipOf(t *testing.T, names ...string) *bytes.Buffer {
+	buf := &bytes.Buffer{}
+	w := zip.NewWriter(buf)
+	for _, name := range names {
+		f, err := w.Create(name)
+		assert.NoError(t, err)
+		f.Write([]byte(name))
+	}
+	assert.NoError(t, w.Close())
+	return buf
+}
+
+func TestExtractZip(t *testing.T) {
+	parent, err := ioutil.TempDir("", "dropbox-zip-test")
+	assert.NoError(t, err)
+	defer os.RemoveAll(parent)
+
+	dir := filepath.Join(parent, "out")
+
+	err = ExtractZip(zipOf(t, "project/", "project/docs/a.txt"), dir)
+	assert.NoError(t, err)
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, "project", "docs", "a.txt"))
+	assert.NoError(t, err)
+	assert.Equal(t, "project/docs/a.txt", string(b))
+
+	err = ExtractZip(zipOf(t, "../evil.txt"), dir)
+	assert.Error(t, err)
+	_, err = os.Stat(filepath.Join(parent, "evil.txt"))
+	assert.True(t, os.IsNotExist(err), "entry should not be written outside dir")
+
+	abs := filepath.Join(parent, "absolute.txt")
+	err = ExtractZip(zipOf(t, filepath.ToSlash(abs)), dir)
+	assert.Error(t, err)
+	_, err = os.Stat(abs)
+	assert.True(t, os.IsNotExist(err), "absolute entry should not be written")
+	_, err = os.Stat(filepath.Join(dir, abs))
+	assert.True(t, os.IsNotExist(err), "absolute entry should not be written below dir")
+}
@@ -0,0 +1,345 @@
+package dropbox
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// FileProperties client for property templates and file properties.
+type FileProperties struct {
+	*Client
+}
+
+// NewFileProperties client.
+func NewFileProperties(config *Config) *FileProperties {
+	return &FileProperties{
+		Client: &Client{
+			Config: config,
+		},
+	}
+}
+
+// Property field limits.
+const (
+	PropertyFieldNameMaxLength  = 256
+	PropertyFieldValueMaxLength = 1024
+)
+
+// Validate checks the field against the name and value length limits.
+func (f *PropertyField) Validate() error {
+	if len(f.Name) > PropertyFieldNameMaxLength {
+		return fmt.Errorf("dropbox: property field name %q exceeds %d bytes", f.Name, PropertyFieldNameMaxLength)
+	}
+
+	if len(f.Value) > PropertyFieldValueMaxLength {
+		return fmt.Errorf("dropbox: property field %q value exceeds %d bytes", f.Name, PropertyFieldValueMaxLength)
+	}
+
+	return nil
+}
+
+// validatePropertyGroups checks every field of the groups.
+func validatePropertyGroups(groups []*PropertyGroup) error {
+	for _, g := range groups {
+		for i := range g.Fields {
+			if err := g.Fields[i].Validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// PropertyType determines the type of a property field.
+type PropertyType string
+
+// PropertyType types supported.
+const (
+	PropertyTypeString PropertyType = "string"
+)
+
+// PropertyFieldTemplate describes a field of a property template.
+type PropertyFieldTemplate struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Type        struct {
+		Tag PropertyType `json:".tag"`
+	} `json:"type"`
+}
+
+// NewPropertyFieldTemplate creates a string PropertyFieldTemplate.
+func NewPropertyFieldTemplate(name, description string) *PropertyFieldTemplate {
+	f := &PropertyFieldTemplate{
+		Name:        name,
+		Description: description,
+	}
+	f.Type.Tag = PropertyTypeString
+	return f
+}
+
+// TemplateOwnerType determines whether a template is owned by a user or team.
+type TemplateOwnerType string
+
+// TemplateOwnerType types supported.
+const (
+	TemplateOwnerUser TemplateOwnerType = "user"
+	TemplateOwnerTeam TemplateOwnerType = "team"
+)
+
+// AddTemplateInput request input.
+type AddTemplateInput struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Fields      []*PropertyFieldTemplate `json:"fields"`
+}
+
+// AddTemplateOutput request output.
+type AddTemplateOutput struct {
+	TemplateID string `json:"template_id"`
+}
+
+// AddTemplate adds a template associated with a user or team.
+func (c *FileProperties) AddTemplate(owner TemplateOwnerType, in *AddTemplateInput) (out *AddTemplateOutput, err error) {
+	body, err := c.call("/file_properties/templates/add_for_"+string(owner), in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// GetTemplateInput request input.
+type GetTemplateInput struct {
+	TemplateID string `json:"template_id"`
+}
+
+// GetTemplateOutput request output.
+type GetTemplateOutput struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"description"`
+	Fields      []*PropertyFieldTemplate `json:"fields"`
+}
+
+// GetTemplate returns the schema for a template.
+func (c *FileProperties) GetTemplate(owner TemplateOwnerType, in *GetTemplateInput) (out *GetTemplateOutput, err error) {
+	body, err := c.call("/file_properties/templates/get_for_"+string(owner), in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// ListTemplatesOutput request output.
+type ListTemplatesOutput struct {
+	TemplateIDs []string `json:"template_ids"`
+}
+
+// ListTemplates returns the identifiers of all templates of a user or team.
+func (c *FileProperties) ListTemplates(owner TemplateOwnerType) (out *ListTemplatesOutput, err error) {
+	body, err := c.call("/file_properties/templates/list_for_"+string(owner), nil)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// UpdateTemplateInput request input.
+type UpdateTemplateInput struct {
+	TemplateID  string                   `json:"template_id"`
+	Name        string                   `json:"name,omitempty"`
+	Description string                   `json:"description,omitempty"`
+	AddFields   []*PropertyFieldTemplate `json:"add_fields,omitempty"`
+}
+
+// UpdateTemplateOutput request output.
+type UpdateTemplateOutput struct {
+	TemplateID string `json:"template_id"`
+}
+
+// UpdateTemplate updates a template. Fields can be added but not removed.
+func (c *FileProperties) UpdateTemplate(owner TemplateOwnerType, in *UpdateTemplateInput) (out *UpdateTemplateOutput, err error) {
+	body, err := c.call("/file_properties/templates/update_for_"+string(owner), in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// AddPropertiesInput request input.
+type AddPropertiesInput struct {
+	Path           string           `json:"path"`
+	PropertyGroups []*PropertyGroup `json:"property_groups"`
+}
+
+// AddProperties adds property groups to a file.
+func (c *FileProperties) AddProperties(in *AddPropertiesInput) (err error) {
+	if err = validatePropertyGroups(in.PropertyGroups); err != nil {
+		return
+	}
+
+	body, err := c.call("/file_properties/properties/add", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	return
+}
+
+// OverwritePropertiesInput request input.
+type OverwritePropertiesInput struct {
+	Path           string           `json:"path"`
+	PropertyGroups []*PropertyGroup `json:"property_groups"`
+}
+
+// OverwriteProperties replaces the property groups of a file.
+func (c *FileProperties) OverwriteProperties(in *OverwritePropertiesInput) (err error) {
+	if err = validatePropertyGroups(in.PropertyGroups); err != nil {
+		return
+	}
+
+	body, err := c.call("/file_properties/properties/overwrite", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	return
+}
+
+// PropertyGroupUpdate specifies the fields to add, update or delete in a property group.
+type PropertyGroupUpdate struct {
+	TemplateID        string          `json:"template_id"`
+	AddOrUpdateFields []PropertyField `json:"add_or_update_fields,omitempty"`
+	RemoveFields      []string        `json:"remove_fields,omitempty"`
+}
+
+// UpdatePropertiesInput request input.
+type UpdatePropertiesInput struct {
+	Path                 string                 `json:"path"`
+	UpdatePropertyGroups []*PropertyGroupUpdate `json:"update_property_groups"`
+}
+
+// UpdateProperties adds, updates or removes fields of property groups on a file.
+func (c *FileProperties) UpdateProperties(in *UpdatePropertiesInput) (err error) {
+	for _, u := range in.UpdatePropertyGroups {
+		for i := range u.AddOrUpdateFields {
+			if err = u.AddOrUpdateFields[i].Validate(); err != nil {
+				return
+			}
+		}
+	}
+
+	body, err := c.call("/file_properties/properties/update", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	return
+}
+
+// RemovePropertiesInput request input.
+type RemovePropertiesInput struct {
+	Path                string   `json:"path"`
+	PropertyTemplateIDs []string `json:"property_template_ids"`
+}
+
+// RemoveProperties removes the property groups of the templates from a file.
+func (c *FileProperties) RemoveProperties(in *RemovePropertiesInput) (err error) {
+	body, err := c.call("/file_properties/properties/remove", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	return
+}
+
+// PropertiesSearchMode determines what a properties search query matches.
+type PropertiesSearchMode struct {
+	Tag       string `json:".tag"` // "field_name".
+	FieldName string `json:"field_name"`
+}
+
+// PropertiesSearchQuery specifies a properties search query.
+type PropertiesSearchQuery struct {
+	Query           string               `json:"query"`
+	Mode            PropertiesSearchMode `json:"mode"`
+	LogicalOperator struct {
+		Tag string `json:".tag"` // "or_operator".
+	} `json:"logical_operator"`
+}
+
+// NewPropertiesSearchQuery creates a query matching values of the field.
+func NewPropertiesSearchQuery(fieldName, query string) *PropertiesSearchQuery {
+	q := &PropertiesSearchQuery{
+		Query: query,
+		Mode: PropertiesSearchMode{
+			Tag:       "field_name",
+			FieldName: fieldName,
+		},
+	}
+	q.LogicalOperator.Tag = "or_operator"
+	return q
+}
+
+// SearchPropertiesInput request input.
+type SearchPropertiesInput struct {
+	Queries        []*PropertiesSearchQuery `json:"queries"`
+	TemplateFilter *TemplateFilterBase      `json:"template_filter,omitempty"`
+}
+
+// PropertiesSearchMatch is a file matching a properties search.
+type PropertiesSearchMatch struct {
+	ID             string           `json:"id"`
+	Path           string           `json:"path"`
+	IsDeleted      bool             `json:"is_deleted"`
+	PropertyGroups []*PropertyGroup `json:"property_groups"`
+}
+
+// SearchPropertiesOutput request output.
+type SearchPropertiesOutput struct {
+	Matches []*PropertiesSearchMatch `json:"matches"`
+	Cursor  string                   `json:"cursor,omitempty"`
+}
+
+// SearchProperties searches across property templates for particular property field values.
+func (c *FileProperties) SearchProperties(in *SearchPropertiesInput) (out *SearchPropertiesOutput, err error) {
+	body, err := c.call("/file_properties/properties/search", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// SearchPropertiesContinueInput request input.
+type SearchPropertiesContinueInput struct {
+	Cursor string `json:"cursor"`
+}
+
+// SearchPropertiesContinue pagenates using the cursor from SearchProperties.
+func (c *FileProperties) SearchPropertiesContinue(in *SearchPropertiesContinueInput) (out *SearchPropertiesOutput, err error) {
+	body, err := c.call("/file_properties/properties/search/continue", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
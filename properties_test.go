@@ -0,0 +1,48 @@
+package dropbox
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileProperties_Templates(t *testing.T) {
+	c := client()
+
+	add, err := c.FileProperties.AddTemplate(TemplateOwnerUser, &AddTemplateInput{
+		Name:        "Security",
+		Description: "These properties describe how confidential this file or folder is.",
+		Fields: []*PropertyFieldTemplate{
+			NewPropertyFieldTemplate("Security Policy", "This is the security policy of the file or folder described."),
+		},
+	})
+	assert.NoError(t, err)
+
+	list, err := c.FileProperties.ListTemplates(TemplateOwnerUser)
+	assert.NoError(t, err)
+	assert.Contains(t, list.TemplateIDs, add.TemplateID)
+
+	out, err := c.FileProperties.GetTemplate(TemplateOwnerUser, &GetTemplateInput{
+		TemplateID: add.TemplateID,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Security", out.Name)
+}
+
+func TestFileProperties_AddProperties_limits(t *testing.T) {
+	c := client()
+
+	err := c.FileProperties.AddProperties(&AddPropertiesInput{
+		Path: "/hello.txt",
+		PropertyGroups: []*PropertyGroup{{
+			TemplateID: "ptid:1a5n2i6d3OYEAAAAAAAAAYa",
+			Fields: []PropertyField{{
+				Name:  "Security Policy",
+				Value: strings.Repeat("x", PropertyFieldValueMaxLength+1),
+			}},
+		}},
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "exceeds 1024 bytes")
+}
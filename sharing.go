@@ -2,6 +2,8 @@ package dropbox
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"time"
 )
 
@@ -19,19 +21,88 @@ func NewSharing(config *Config) *Sharing {
 	}
 }
 
+// LinkAudience determines who can access a shared link.
+type LinkAudience string
+
+// LinkAudience types supported.
+const (
+	LinkAudiencePublic   LinkAudience = "public"
+	LinkAudienceTeam     LinkAudience = "team"
+	LinkAudienceNoOne    LinkAudience = "no_one"
+	LinkAudiencePassword LinkAudience = "password"
+	LinkAudienceMembers  LinkAudience = "members"
+)
+
+// LinkAccessLevel determines the access a shared link grants.
+type LinkAccessLevel string
+
+// LinkAccessLevel types supported.
+const (
+	LinkAccessLevelViewer  LinkAccessLevel = "viewer"
+	LinkAccessLevelEditor  LinkAccessLevel = "editor"
+	LinkAccessLevelMax     LinkAccessLevel = "max"     // Request only.
+	LinkAccessLevelDefault LinkAccessLevel = "default" // Request only.
+)
+
+// SharedLinkSettings specifies the settings of a shared link.
+type SharedLinkSettings struct {
+	RequirePassword     bool            `json:"require_password,omitempty"`
+	LinkPassword        string          `json:"link_password,omitempty"`
+	Expires             *time.Time      `json:"expires,omitempty"`
+	Audience            LinkAudience    `json:"audience,omitempty"`
+	Access              LinkAccessLevel `json:"access,omitempty"`
+	RequestedVisibility VisibilityType  `json:"requested_visibility,omitempty"`
+	AllowDownload       *bool           `json:"allow_download,omitempty"`
+}
+
+// SetExpires sets the expiry, truncated to whole seconds as Dropbox requires.
+func (s *SharedLinkSettings) SetExpires(t time.Time) {
+	t = t.UTC().Truncate(time.Second)
+	s.Expires = &t
+}
+
+// SetPassword requires the password to access the link.
+func (s *SharedLinkSettings) SetPassword(password string) {
+	s.RequirePassword = true
+	s.LinkPassword = password
+}
+
 // CreateSharedLinkInput request input.
 type CreateSharedLinkInput struct {
-	Path string `json:"path"`
+	Path     string              `json:"path"`
+	Settings *SharedLinkSettings `json:"settings,omitempty"`
+}
+
+// LinkPermissions specifies what the current user can do with a shared link.
+type LinkPermissions struct {
+	CanRevoke          bool `json:"can_revoke"`
+	ResolvedVisibility *struct {
+		Tag VisibilityType `json:".tag"`
+	} `json:"resolved_visibility,omitempty"`
+	RequestedVisibility *struct {
+		Tag VisibilityType `json:".tag"`
+	} `json:"requested_visibility,omitempty"`
+	RevokeFailureReason *struct {
+		Tag string `json:".tag"`
+	} `json:"revoke_failure_reason,omitempty"`
+	EffectiveAudience *struct {
+		Tag LinkAudience `json:".tag"`
+	} `json:"effective_audience,omitempty"`
+	LinkAccessLevel *struct {
+		Tag LinkAccessLevel `json:".tag"`
+	} `json:"link_access_level,omitempty"`
+	AllowDownload     bool `json:"allow_download"`
+	AllowComments     bool `json:"allow_comments"`
+	RequirePassword   bool `json:"require_password"`
+	CanSetExpiry      bool `json:"can_set_expiry"`
+	CanRemoveExpiry   bool `json:"can_remove_expiry"`
+	CanSetPassword    bool `json:"can_set_password"`
+	CanRemovePassword bool `json:"can_remove_password"`
 }
 
 // CreateSharedLinkOutput request output.
 type CreateSharedLinkOutput struct {
-	URL             string `json:"url"`
-	Path            string `json:"path"`
-	VisibilityModel struct {
-		Tag VisibilityType `json:".tag"`
-	} `json:"visibility"`
-	Expires time.Time `json:"expires,omitempty"`
+	SharedLinkOutput
 }
 
 // VisibilityType determines who can access the link.
@@ -46,9 +117,20 @@ const (
 	SharedFolderOnly                = "shared_folder_only"
 )
 
-// CreateSharedLink returns a shared link.
+// CreateSharedLink returns a shared link. When the path already has a shared
+// link, the existing link is returned.
 func (c *Sharing) CreateSharedLink(in *CreateSharedLinkInput) (out *CreateSharedLinkOutput, err error) {
 	body, err := c.call("/sharing/create_shared_link_with_settings", in)
+	if e, ok := err.(*Error); ok && e.Tag == "shared_link_already_exists" {
+		var details struct {
+			Existing *struct {
+				Metadata *SharedLinkOutput `json:"metadata"`
+			} `json:"shared_link_already_exists"`
+		}
+		if e.decodeDetails(&details) == nil && details.Existing != nil && details.Existing.Metadata != nil {
+			return &CreateSharedLinkOutput{*details.Existing.Metadata}, nil
+		}
+	}
 	if err != nil {
 		return
 	}
@@ -58,24 +140,35 @@ func (c *Sharing) CreateSharedLink(in *CreateSharedLinkInput) (out *CreateShared
 	return
 }
 
-// ListShareLinksInput request input.
+// ListShareLinksInput request input. Leave Path empty to list all links of the current user.
 type ListShareLinksInput struct {
-	Path string `json:"path"`
+	Path       string `json:"path,omitempty"`
+	Cursor     string `json:"cursor,omitempty"`
+	DirectOnly bool   `json:"direct_only,omitempty"`
 }
 
-// SharedLinkOutput request output.
+// SharedLinkOutput is the metadata of a shared link to a file or folder.
+// Tag is "file" or "folder"; the modification times, Rev and Size are only
+// set for files.
 type SharedLinkOutput struct {
-	URL             string `json:"url"`
-	Path            string `json:"path"`
-	VisibilityModel struct {
-		Tag VisibilityType `json:".tag"`
-	} `json:"visibility"`
-	Expires time.Time `json:"expires,omitempty"`
+	Tag             string           `json:".tag"`
+	URL             string           `json:"url"`
+	ID              string           `json:"id,omitempty"`
+	Name            string           `json:"name"`
+	PathLower       string           `json:"path_lower,omitempty"`
+	Expires         *time.Time       `json:"expires,omitempty"`
+	LinkPermissions *LinkPermissions `json:"link_permissions"`
+	ClientModified  time.Time        `json:"client_modified,omitempty"`
+	ServerModified  time.Time        `json:"server_modified,omitempty"`
+	Rev             string           `json:"rev,omitempty"`
+	Size            uint64           `json:"size,omitempty"`
 }
 
 // ListShareLinksOutput request output.
 type ListShareLinksOutput struct {
-	Links []SharedLinkOutput `json:"links"`
+	Links   []SharedLinkOutput `json:"links"`
+	HasMore bool               `json:"has_more"`
+	Cursor  string             `json:"cursor,omitempty"`
 }
 
 // ListSharedLinks gets shared links of input.
@@ -91,6 +184,154 @@ func (c *Sharing) ListSharedLinks(in *ListShareLinksInput) (out *ListShareLinksO
 	return
 }
 
+// SharedLinkIterator iterates over shared links across pages.
+type SharedLinkIterator struct {
+	sharing *Sharing
+	in      ListShareLinksInput
+	page    []SharedLinkOutput
+	out     *ListShareLinksOutput
+	link    *SharedLinkOutput
+	err     error
+}
+
+// ListSharedLinksIter returns an iterator over the shared links of in,
+// fetching further pages using the cursor.
+func (c *Sharing) ListSharedLinksIter(in *ListShareLinksInput) *SharedLinkIterator {
+	return &SharedLinkIterator{
+		sharing: c,
+		in:      *in,
+	}
+}
+
+// Next advances to the next link, returning false when there are no more
+// links or an error occurred.
+func (it *SharedLinkIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	for len(it.page) == 0 {
+		if it.out != nil {
+			if !it.out.HasMore {
+				return false
+			}
+			it.in.Cursor = it.out.Cursor
+		}
+
+		it.out, it.err = it.sharing.ListSharedLinks(&it.in)
+		if it.err != nil {
+			return false
+		}
+
+		it.page = it.out.Links
+	}
+
+	it.link, it.page = &it.page[0], it.page[1:]
+	return true
+}
+
+// Link returns the current link.
+func (it *SharedLinkIterator) Link() *SharedLinkOutput {
+	return it.link
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *SharedLinkIterator) Err() error {
+	return it.err
+}
+
+// ModifySharedLinkSettingsInput request input.
+type ModifySharedLinkSettingsInput struct {
+	URL              string              `json:"url"`
+	Settings         *SharedLinkSettings `json:"settings"`
+	RemoveExpiration bool                `json:"remove_expiration,omitempty"`
+}
+
+// ModifySharedLinkSettingsOutput request output.
+type ModifySharedLinkSettingsOutput struct {
+	SharedLinkOutput
+}
+
+// ModifySharedLinkSettings changes the settings of a shared link.
+func (c *Sharing) ModifySharedLinkSettings(in *ModifySharedLinkSettingsInput) (out *ModifySharedLinkSettingsOutput, err error) {
+	body, err := c.call("/sharing/modify_shared_link_settings", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// RevokeSharedLinkInput request input.
+type RevokeSharedLinkInput struct {
+	URL string `json:"url"`
+}
+
+// RevokeSharedLink revokes a shared link.
+func (c *Sharing) RevokeSharedLink(in *RevokeSharedLinkInput) (err error) {
+	body, err := c.call("/sharing/revoke_shared_link", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	return
+}
+
+// GetSharedLinkMetadataInput request input. Path selects a file inside a
+// shared folder link, relative to the folder.
+type GetSharedLinkMetadataInput struct {
+	URL          string `json:"url"`
+	Path         string `json:"path,omitempty"`
+	LinkPassword string `json:"link_password,omitempty"`
+}
+
+// GetSharedLinkMetadataOutput request output.
+type GetSharedLinkMetadataOutput struct {
+	SharedLinkOutput
+}
+
+// GetSharedLinkMetadata returns the metadata of a shared link.
+func (c *Sharing) GetSharedLinkMetadata(in *GetSharedLinkMetadataInput) (out *GetSharedLinkMetadataOutput, err error) {
+	body, err := c.call("/sharing/get_shared_link_metadata", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// GetSharedLinkFileInput request input. Path selects a file inside a shared
+// folder link, relative to the folder.
+type GetSharedLinkFileInput struct {
+	URL          string `json:"url"`
+	Path         string `json:"path,omitempty"`
+	LinkPassword string `json:"link_password,omitempty"`
+}
+
+// GetSharedLinkFileOutput request output.
+type GetSharedLinkFileOutput struct {
+	Body     io.ReadCloser     `json:"-"`
+	Length   int64             `json:"-"`
+	Metadata *SharedLinkOutput `json:"-"`
+}
+
+// GetSharedLinkFile downloads the file of a shared link.
+func (c *Sharing) GetSharedLinkFile(in *GetSharedLinkFileInput) (out *GetSharedLinkFileOutput, err error) {
+	meta := &SharedLinkOutput{}
+	body, l, err := c.downloadResult("/sharing/get_shared_link_file", in, meta)
+	if err != nil {
+		return
+	}
+
+	out = &GetSharedLinkFileOutput{body, l, meta}
+	return
+}
+
 // ListSharedFolderInput request input.
 type ListSharedFolderInput struct {
 	Limit   uint64         `json:"limit"`
@@ -98,8 +339,35 @@ type ListSharedFolderInput struct {
 }
 
 // FolderAction defines actions that may be taken on shared folders.
-type FolderAction struct {
-	ChangeOptions string
+type FolderAction string
+
+// FolderAction types supported.
+const (
+	FolderActionChangeOptions         FolderAction = "change_options"
+	FolderActionDisableViewerInfo     FolderAction = "disable_viewer_info"
+	FolderActionEditContents          FolderAction = "edit_contents"
+	FolderActionEnableViewerInfo      FolderAction = "enable_viewer_info"
+	FolderActionInviteEditor          FolderAction = "invite_editor"
+	FolderActionInviteViewer          FolderAction = "invite_viewer"
+	FolderActionInviteViewerNoComment FolderAction = "invite_viewer_no_comment"
+	FolderActionRelinquishMembership  FolderAction = "relinquish_membership"
+	FolderActionUnmount               FolderAction = "unmount"
+	FolderActionUnshare               FolderAction = "unshare"
+	FolderActionLeaveACopy            FolderAction = "leave_a_copy"
+	FolderActionCreateLink            FolderAction = "create_link"
+	FolderActionSetAccessInheritance  FolderAction = "set_access_inheritance"
+)
+
+// FolderPermission specifies whether the current user may perform an action
+// on a shared folder, and if not, why.
+type FolderPermission struct {
+	Action struct {
+		Tag FolderAction `json:".tag"`
+	} `json:"action"`
+	Allow  bool `json:"allow"`
+	Reason *struct {
+		Tag string `json:".tag"`
+	} `json:"reason,omitempty"`
 }
 
 // ListSharedFolderOutput lists metadata about shared folders with a cursor to retrieve the next page.
@@ -151,9 +419,35 @@ type SharedFolderMetadata struct {
 		ID   string `json:"id"`
 		Name string `json:"name"`
 	} `json:"owner_team"`
-	ParentSharedFolderID string   `json:"parent_shared_folder_id"`
-	PathLower            string   `json:"path_lower"`
-	Permissions          []string `json:"permissions"`
+	ParentSharedFolderID string              `json:"parent_shared_folder_id"`
+	PathLower            string              `json:"path_lower"`
+	Permissions          []*FolderPermission `json:"permissions"` // Only set for actions requested in the input.
+	AccessInheritance    struct {
+		Tag AccessInheritance `json:".tag"`
+	} `json:"access_inheritance"`
+}
+
+// InheritsAccess returns true unless the folder breaks inheritance of
+// members from its parent folder.
+func (m *SharedFolderMetadata) InheritsAccess() bool {
+	return m.AccessInheritance.Tag != AccessInheritanceNoInherit
+}
+
+// CanPerform returns true if the current user may perform action on the
+// folder. Actions which weren't requested are reported as not permitted.
+func (m *SharedFolderMetadata) CanPerform(action FolderAction) bool {
+	p := m.Permission(action)
+	return p != nil && p.Allow
+}
+
+// Permission returns the permission for action, or nil if it wasn't requested.
+func (m *SharedFolderMetadata) Permission(action FolderAction) *FolderPermission {
+	for _, p := range m.Permissions {
+		if p.Action.Tag == action {
+			return p
+		}
+	}
+	return nil
 }
 
 // FolderPolicy enumerates the policies governing this shared folder.
@@ -209,3 +503,888 @@ const (
 	MemberPolicyTeam   MemberPolicy = "team"
 	MemberPolicyAnyone              = "anyone"
 )
+
+// SharingJobError describes why an async sharing job failed.
+type SharingJobError struct {
+	Tag string `json:".tag"`
+}
+
+// Error string.
+func (e *SharingJobError) Error() string {
+	return fmt.Sprintf("dropbox: sharing job failed: %s", e.Tag)
+}
+
+// AsyncJobLaunch is returned by endpoints which always start an async job.
+type AsyncJobLaunch struct {
+	Tag        string `json:".tag"`
+	AsyncJobID string `json:"async_job_id"`
+}
+
+// ShareFolderInput request input.
+type ShareFolderInput struct {
+	Path             string           `json:"path"`
+	ACLUpdatePolicy  ACLUpdatePolicy  `json:"acl_update_policy,omitempty"`
+	ForceAsync       bool             `json:"force_async,omitempty"`
+	MemberPolicy     MemberPolicy     `json:"member_policy,omitempty"`
+	SharedLinkPolicy SharedLinkPolicy `json:"shared_link_policy,omitempty"`
+}
+
+// ShareFolderOutput request output. Tag is either AsyncJobID or AsyncJobComplete.
+type ShareFolderOutput struct {
+	Tag        string                `json:".tag"`
+	AsyncJobID string                `json:"async_job_id,omitempty"`
+	Complete   *SharedFolderMetadata `json:"-"`
+}
+
+// UnmarshalJSON decodes the shared folder metadata when complete.
+func (o *ShareFolderOutput) UnmarshalJSON(b []byte) error {
+	type output ShareFolderOutput
+	if err := json.Unmarshal(b, (*output)(o)); err != nil {
+		return err
+	}
+
+	if o.Tag != AsyncJobComplete {
+		return nil
+	}
+
+	o.Complete = &SharedFolderMetadata{}
+	return json.Unmarshal(b, o.Complete)
+}
+
+// ShareFolder shares a folder with collaborators. Sharing large folders
+// completes asynchronously.
+func (c *Sharing) ShareFolder(in *ShareFolderInput) (out *ShareFolderOutput, err error) {
+	body, err := c.call("/sharing/share_folder", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// CheckShareJobStatusInput request input.
+type CheckShareJobStatusInput struct {
+	AsyncJobID string `json:"async_job_id"`
+}
+
+// CheckShareJobStatusOutput request output. Tag is one of AsyncJobInProgress,
+// AsyncJobComplete or AsyncJobFailed.
+type CheckShareJobStatusOutput struct {
+	Tag      string                `json:".tag"`
+	Complete *SharedFolderMetadata `json:"-"`
+	Failed   *SharingJobError      `json:"failed,omitempty"`
+}
+
+// UnmarshalJSON decodes the shared folder metadata when complete.
+func (o *CheckShareJobStatusOutput) UnmarshalJSON(b []byte) error {
+	type output CheckShareJobStatusOutput
+	if err := json.Unmarshal(b, (*output)(o)); err != nil {
+		return err
+	}
+
+	if o.Tag != AsyncJobComplete {
+		return nil
+	}
+
+	o.Complete = &SharedFolderMetadata{}
+	return json.Unmarshal(b, o.Complete)
+}
+
+// status implements jobStatus.
+func (o *CheckShareJobStatusOutput) status() (string, error) {
+	if o.Failed == nil {
+		return o.Tag, &SharingJobError{}
+	}
+	return o.Tag, o.Failed
+}
+
+// CheckShareJobStatus checks the status of a ShareFolder job.
+func (c *Sharing) CheckShareJobStatus(in *CheckShareJobStatusInput) (out *CheckShareJobStatusOutput, err error) {
+	body, err := c.call("/sharing/check_share_job_status", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// ShareFolderAndWait shares a folder, polling the job with backoff until it
+// completes. A failed job returns a *SharingJobError.
+func (c *Sharing) ShareFolderAndWait(in *ShareFolderInput) (*SharedFolderMetadata, error) {
+	out, err := c.ShareFolder(in)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.waitForShareJob(out)
+}
+
+// waitForShareJob polls a job launched by ShareFolder or SetAccessInheritance.
+func (c *Sharing) waitForShareJob(out *ShareFolderOutput) (*SharedFolderMetadata, error) {
+	if out.Tag == AsyncJobComplete {
+		return out.Complete, nil
+	}
+
+	var folder *SharedFolderMetadata
+	err := waitForJob(func() (jobStatus, error) {
+		status, err := c.CheckShareJobStatus(&CheckShareJobStatusInput{
+			AsyncJobID: out.AsyncJobID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		folder = status.Complete
+		return status, nil
+	})
+
+	return folder, err
+}
+
+// MemberSelector selects a member by email or Dropbox ID.
+type MemberSelector struct {
+	Tag       string `json:".tag"`
+	Email     string `json:"email,omitempty"`
+	DropboxID string `json:"dropbox_id,omitempty"`
+}
+
+// NewEmailMember creates MemberSelector for an email address.
+func NewEmailMember(email string) *MemberSelector {
+	return &MemberSelector{
+		Tag:   "email",
+		Email: email,
+	}
+}
+
+// NewDropboxIDMember creates MemberSelector for an account or group ID.
+func NewDropboxIDMember(id string) *MemberSelector {
+	return &MemberSelector{
+		Tag:       "dropbox_id",
+		DropboxID: id,
+	}
+}
+
+// AddMember specifies a member to add and their access level.
+type AddMember struct {
+	Member      *MemberSelector `json:"member"`
+	AccessLevel AccessType      `json:"access_level,omitempty"`
+}
+
+// AddFolderMemberInput request input.
+type AddFolderMemberInput struct {
+	SharedFolderID string       `json:"shared_folder_id"`
+	Members        []*AddMember `json:"members"`
+	Quiet          bool         `json:"quiet,omitempty"`
+	CustomMessage  string       `json:"custom_message,omitempty"`
+}
+
+// AddFolderMember invites members to a shared folder.
+func (c *Sharing) AddFolderMember(in *AddFolderMemberInput) (err error) {
+	body, err := c.call("/sharing/add_folder_member", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	return
+}
+
+// RemoveFolderMemberInput request input.
+type RemoveFolderMemberInput struct {
+	SharedFolderID string          `json:"shared_folder_id"`
+	Member         *MemberSelector `json:"member"`
+	LeaveACopy     bool            `json:"leave_a_copy"`
+}
+
+// RemoveFolderMemberOutput request output. Its job can only be checked with
+// CheckRemoveMemberJobStatus, not WaitForJob.
+type RemoveFolderMemberOutput struct {
+	Tag        string `json:".tag"`
+	AsyncJobID string `json:"async_job_id"`
+}
+
+// RemoveFolderMember removes a member from a shared folder. The removal
+// completes asynchronously, see RemoveFolderMemberAndWait.
+func (c *Sharing) RemoveFolderMember(in *RemoveFolderMemberInput) (out *RemoveFolderMemberOutput, err error) {
+	body, err := c.call("/sharing/remove_folder_member", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// CheckRemoveMemberJobStatusInput request input.
+type CheckRemoveMemberJobStatusInput struct {
+	AsyncJobID string `json:"async_job_id"`
+}
+
+// CheckRemoveMemberJobStatusOutput request output. Tag is one of
+// AsyncJobInProgress, AsyncJobComplete or AsyncJobFailed.
+type CheckRemoveMemberJobStatusOutput struct {
+	Tag         string `json:".tag"`
+	AccessLevel *struct {
+		Tag AccessType `json:".tag"`
+	} `json:"access_level,omitempty"`
+	Failed *SharingJobError `json:"failed,omitempty"`
+}
+
+// status implements jobStatus.
+func (o *CheckRemoveMemberJobStatusOutput) status() (string, error) {
+	if o.Failed == nil {
+		return o.Tag, &SharingJobError{}
+	}
+	return o.Tag, o.Failed
+}
+
+// CheckRemoveMemberJobStatus checks the status of a RemoveFolderMember job.
+func (c *Sharing) CheckRemoveMemberJobStatus(in *CheckRemoveMemberJobStatusInput) (out *CheckRemoveMemberJobStatusOutput, err error) {
+	body, err := c.call("/sharing/check_remove_member_job_status", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// RemoveFolderMemberAndWait removes a member from a shared folder, polling
+// the job with backoff until it completes. A failed job returns a
+// *SharingJobError.
+func (c *Sharing) RemoveFolderMemberAndWait(in *RemoveFolderMemberInput) error {
+	out, err := c.RemoveFolderMember(in)
+	if err != nil {
+		return err
+	}
+
+	return waitForJob(func() (jobStatus, error) {
+		status, err := c.CheckRemoveMemberJobStatus(&CheckRemoveMemberJobStatusInput{
+			AsyncJobID: out.AsyncJobID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return status, nil
+	})
+}
+
+// UpdateFolderMemberInput request input.
+type UpdateFolderMemberInput struct {
+	SharedFolderID string          `json:"shared_folder_id"`
+	Member         *MemberSelector `json:"member"`
+	AccessLevel    AccessType      `json:"access_level"`
+}
+
+// UpdateFolderMemberOutput request output.
+type UpdateFolderMemberOutput struct {
+	AccessLevel *struct {
+		Tag AccessType `json:".tag"`
+	} `json:"access_level,omitempty"`
+	Warning string `json:"warning,omitempty"`
+}
+
+// UpdateFolderMember changes a member's access level in a shared folder.
+func (c *Sharing) UpdateFolderMember(in *UpdateFolderMemberInput) (out *UpdateFolderMemberOutput, err error) {
+	body, err := c.call("/sharing/update_folder_member", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// UserInfo specifies a user who is a member of a shared folder or file.
+type UserInfo struct {
+	AccountID    string `json:"account_id"`
+	Email        string `json:"email"`
+	DisplayName  string `json:"display_name"`
+	SameTeam     bool   `json:"same_team"`
+	TeamMemberID string `json:"team_member_id,omitempty"`
+}
+
+// UserMembershipInfo specifies a user's membership.
+type UserMembershipInfo struct {
+	AccessType struct {
+		Tag AccessType `json:".tag"`
+	} `json:"access_type"`
+	User        UserInfo `json:"user"`
+	Initials    string   `json:"initials,omitempty"`
+	IsInherited bool     `json:"is_inherited"`
+}
+
+// GroupMembershipInfo specifies a group's membership.
+type GroupMembershipInfo struct {
+	AccessType struct {
+		Tag AccessType `json:".tag"`
+	} `json:"access_type"`
+	Group struct {
+		GroupName   string `json:"group_name"`
+		GroupID     string `json:"group_id"`
+		MemberCount uint64 `json:"member_count,omitempty"`
+	} `json:"group"`
+	IsInherited bool `json:"is_inherited"`
+}
+
+// InviteeMembershipInfo specifies an invitee who hasn't joined yet.
+type InviteeMembershipInfo struct {
+	AccessType struct {
+		Tag AccessType `json:".tag"`
+	} `json:"access_type"`
+	Invitee struct {
+		Tag   string `json:".tag"`
+		Email string `json:"email,omitempty"`
+	} `json:"invitee"`
+	User        *UserInfo `json:"user,omitempty"`
+	IsInherited bool      `json:"is_inherited"`
+}
+
+// ListFolderMembersInput request input.
+type ListFolderMembersInput struct {
+	SharedFolderID string `json:"shared_folder_id"`
+	Limit          uint64 `json:"limit,omitempty"` // min=1, max=1000
+}
+
+// ListFolderMembersOutput request output.
+type ListFolderMembersOutput struct {
+	Users    []*UserMembershipInfo    `json:"users"`
+	Groups   []*GroupMembershipInfo   `json:"groups"`
+	Invitees []*InviteeMembershipInfo `json:"invitees"`
+	Cursor   string                   `json:"cursor,omitempty"`
+}
+
+// ListFolderMembers returns the members of a shared folder.
+func (c *Sharing) ListFolderMembers(in *ListFolderMembersInput) (out *ListFolderMembersOutput, err error) {
+	body, err := c.call("/sharing/list_folder_members", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// ListFolderMembersContinueInput request input.
+type ListFolderMembersContinueInput struct {
+	Cursor string `json:"cursor"`
+}
+
+// ListFolderMembersContinue pagenates using the cursor from ListFolderMembers.
+func (c *Sharing) ListFolderMembersContinue(in *ListFolderMembersContinueInput) (out *ListFolderMembersOutput, err error) {
+	body, err := c.call("/sharing/list_folder_members/continue", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// UpdateFolderPolicyInput request input. Empty policies are left unchanged.
+type UpdateFolderPolicyInput struct {
+	SharedFolderID   string           `json:"shared_folder_id"`
+	MemberPolicy     MemberPolicy     `json:"member_policy,omitempty"`
+	ACLUpdatePolicy  ACLUpdatePolicy  `json:"acl_update_policy,omitempty"`
+	SharedLinkPolicy SharedLinkPolicy `json:"shared_link_policy,omitempty"`
+}
+
+// UpdateFolderPolicyOutput request output.
+type UpdateFolderPolicyOutput struct {
+	SharedFolderMetadata
+}
+
+// UpdateFolderPolicy changes the policies of a shared folder.
+func (c *Sharing) UpdateFolderPolicy(in *UpdateFolderPolicyInput) (out *UpdateFolderPolicyOutput, err error) {
+	body, err := c.call("/sharing/update_folder_policy", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// CheckJobStatusInput request input.
+type CheckJobStatusInput struct {
+	AsyncJobID string `json:"async_job_id"`
+}
+
+// CheckJobStatusOutput request output. Tag is one of AsyncJobInProgress,
+// AsyncJobComplete or AsyncJobFailed.
+type CheckJobStatusOutput struct {
+	Tag    string           `json:".tag"`
+	Failed *SharingJobError `json:"failed,omitempty"`
+}
+
+// status implements jobStatus.
+func (o *CheckJobStatusOutput) status() (string, error) {
+	if o.Failed == nil {
+		return o.Tag, &SharingJobError{}
+	}
+	return o.Tag, o.Failed
+}
+
+// CheckJobStatus checks the status of a sharing job which has no result,
+// such as RelinquishFolderMembership or UnshareFolder.
+func (c *Sharing) CheckJobStatus(in *CheckJobStatusInput) (out *CheckJobStatusOutput, err error) {
+	body, err := c.call("/sharing/check_job_status", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// WaitForJob polls the job with backoff until it completes. It returns
+// immediately when the launch already completed synchronously. A failed job
+// returns a *SharingJobError.
+func (c *Sharing) WaitForJob(launch *AsyncJobLaunch) error {
+	if launch.Tag == AsyncJobComplete {
+		return nil
+	}
+
+	return waitForJob(func() (jobStatus, error) {
+		status, err := c.CheckJobStatus(&CheckJobStatusInput{
+			AsyncJobID: launch.AsyncJobID,
+		})
+		if err != nil {
+			return nil, err
+		}
+		return status, nil
+	})
+}
+
+// MountFolderInput request input.
+type MountFolderInput struct {
+	SharedFolderID string `json:"shared_folder_id"`
+}
+
+// MountFolderOutput request output.
+type MountFolderOutput struct {
+	SharedFolderMetadata
+}
+
+// MountFolder accepts an invitation to a shared folder, adding it to the
+// current user's Dropbox.
+func (c *Sharing) MountFolder(in *MountFolderInput) (out *MountFolderOutput, err error) {
+	body, err := c.call("/sharing/mount_folder", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// UnmountFolderInput request input.
+type UnmountFolderInput struct {
+	SharedFolderID string `json:"shared_folder_id"`
+}
+
+// UnmountFolder removes a shared folder from the current user's Dropbox
+// while keeping their membership, so it can be mounted again.
+func (c *Sharing) UnmountFolder(in *UnmountFolderInput) (err error) {
+	body, err := c.call("/sharing/unmount_folder", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	return
+}
+
+// RelinquishFolderMembershipInput request input.
+type RelinquishFolderMembershipInput struct {
+	SharedFolderID string `json:"shared_folder_id"`
+	LeaveACopy     bool   `json:"leave_a_copy,omitempty"`
+}
+
+// RelinquishFolderMembership leaves a shared folder. It may complete
+// asynchronously, see WaitForJob.
+func (c *Sharing) RelinquishFolderMembership(in *RelinquishFolderMembershipInput) (out *AsyncJobLaunch, err error) {
+	body, err := c.call("/sharing/relinquish_folder_membership", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// TransferFolderInput request input.
+type TransferFolderInput struct {
+	SharedFolderID string `json:"shared_folder_id"`
+	ToDropboxID    string `json:"to_dropbox_id"`
+}
+
+// TransferFolder transfers ownership of a shared folder to a member.
+func (c *Sharing) TransferFolder(in *TransferFolderInput) (err error) {
+	body, err := c.call("/sharing/transfer_folder", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	return
+}
+
+// UnshareFolderInput request input.
+type UnshareFolderInput struct {
+	SharedFolderID string `json:"shared_folder_id"`
+	LeaveACopy     bool   `json:"leave_a_copy,omitempty"`
+}
+
+// UnshareFolder stops sharing a folder, removing all members. It may
+// complete asynchronously, see WaitForJob.
+func (c *Sharing) UnshareFolder(in *UnshareFolderInput) (out *AsyncJobLaunch, err error) {
+	body, err := c.call("/sharing/unshare_folder", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// ListMountableFolders returns the shared folders the current user can mount
+// or unmount.
+func (c *Sharing) ListMountableFolders(in *ListSharedFolderInput) (out *ListSharedFolderOutput, err error) {
+	body, err := c.call("/sharing/list_mountable_folders", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// ListMountableFoldersContinue pagenates using the cursor from ListMountableFolders.
+func (c *Sharing) ListMountableFoldersContinue(in *ListSharedFolderContinueInput) (out *ListSharedFolderOutput, err error) {
+	body, err := c.call("/sharing/list_mountable_folders/continue", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// AddFileMemberInput request input.
+type AddFileMemberInput struct {
+	File                string            `json:"file"` // Path or ID.
+	Members             []*MemberSelector `json:"members"`
+	CustomMessage       string            `json:"custom_message,omitempty"`
+	Quiet               bool              `json:"quiet,omitempty"`
+	AccessLevel         AccessType        `json:"access_level,omitempty"`
+	AddMessageAsComment bool              `json:"add_message_as_comment,omitempty"`
+}
+
+// FileMemberActionResult is the outcome of adding a member to a file. Result
+// Tag is "success" or "member_error".
+type FileMemberActionResult struct {
+	Member *MemberSelector `json:"member"`
+	Result struct {
+		Tag         string `json:".tag"`
+		MemberError *struct {
+			Tag string `json:".tag"`
+		} `json:"member_error,omitempty"`
+	} `json:"result"`
+}
+
+// AddFileMember shares a file with members.
+func (c *Sharing) AddFileMember(in *AddFileMemberInput) (out []*FileMemberActionResult, err error) {
+	body, err := c.call("/sharing/add_file_member", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// RemoveFileMemberInput request input.
+type RemoveFileMemberInput struct {
+	File   string          `json:"file"` // Path or ID.
+	Member *MemberSelector `json:"member"`
+}
+
+// RemoveFileMemberOutput request output. Tag is "success" or "member_error".
+type RemoveFileMemberOutput struct {
+	Tag         string `json:".tag"`
+	MemberError *struct {
+		Tag string `json:".tag"`
+	} `json:"member_error,omitempty"`
+}
+
+// RemoveFileMember removes a member from a file.
+func (c *Sharing) RemoveFileMember(in *RemoveFileMemberInput) (out *RemoveFileMemberOutput, err error) {
+	body, err := c.call("/sharing/remove_file_member_2", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// ListFileMembersInput request input.
+type ListFileMembersInput struct {
+	File             string `json:"file"` // Path or ID.
+	IncludeInherited bool   `json:"include_inherited"`
+	Limit            uint64 `json:"limit,omitempty"` // min=1, max=300
+}
+
+// NewListFileMembersInput creates ListFileMembersInput and set default values.
+func NewListFileMembersInput() *ListFileMembersInput {
+	return &ListFileMembersInput{
+		IncludeInherited: true,
+		Limit:            100,
+	}
+}
+
+// ListFileMembersOutput request output.
+type ListFileMembersOutput struct {
+	ListFolderMembersOutput
+}
+
+// ListFileMembers returns the members of a file.
+func (c *Sharing) ListFileMembers(in *ListFileMembersInput) (out *ListFileMembersOutput, err error) {
+	body, err := c.call("/sharing/list_file_members", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// ListFileMembersContinueInput request input.
+type ListFileMembersContinueInput struct {
+	Cursor string `json:"cursor"`
+}
+
+// ListFileMembersContinue pagenates using the cursor from ListFileMembers.
+func (c *Sharing) ListFileMembersContinue(in *ListFileMembersContinueInput) (out *ListFileMembersOutput, err error) {
+	body, err := c.call("/sharing/list_file_members/continue", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// ListFileMembersBatchInput request input.
+type ListFileMembersBatchInput struct {
+	Files []string `json:"files"`           // Paths or IDs, max=100.
+	Limit uint64   `json:"limit,omitempty"` // Members per file, max=20.
+}
+
+// ListFileMembersBatchResult is the result for a single file. Result Tag is
+// "result", with Members set, or "access_error".
+type ListFileMembersBatchResult struct {
+	File   string `json:"file"`
+	Result struct {
+		Tag         string                 `json:".tag"`
+		Members     *ListFileMembersOutput `json:"members,omitempty"`
+		MemberCount uint64                 `json:"member_count,omitempty"`
+		AccessError *struct {
+			Tag string `json:".tag"`
+		} `json:"access_error,omitempty"`
+	} `json:"result"`
+}
+
+// ListFileMembersBatch returns the first members of many files.
+func (c *Sharing) ListFileMembersBatch(in *ListFileMembersBatchInput) (out []*ListFileMembersBatchResult, err error) {
+	body, err := c.call("/sharing/list_file_members/batch", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// SharedFileMetadata includes basic information about a shared file.
+type SharedFileMetadata struct {
+	ID         string       `json:"id"`
+	Name       string       `json:"name"`
+	PreviewURL string       `json:"preview_url"`
+	Policy     FolderPolicy `json:"policy"`
+	AccessType *struct {
+		Tag AccessType `json:".tag"`
+	} `json:"access_type,omitempty"`
+	OwnerDisplayNames []string `json:"owner_display_names,omitempty"`
+	OwnerTeam         *struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"owner_team,omitempty"`
+	ParentSharedFolderID string     `json:"parent_shared_folder_id,omitempty"`
+	PathDisplay          string     `json:"path_display,omitempty"`
+	PathLower            string     `json:"path_lower,omitempty"`
+	TimeInvited          *time.Time `json:"time_invited,omitempty"`
+}
+
+// GetFileMetadataInput request input.
+type GetFileMetadataInput struct {
+	File string `json:"file"` // Path or ID.
+}
+
+// GetFileMetadataOutput request output.
+type GetFileMetadataOutput struct {
+	SharedFileMetadata
+}
+
+// GetFileMetadata returns the sharing metadata of a file.
+func (c *Sharing) GetFileMetadata(in *GetFileMetadataInput) (out *GetFileMetadataOutput, err error) {
+	body, err := c.call("/sharing/get_file_metadata", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// ListReceivedFilesInput request input.
+type ListReceivedFilesInput struct {
+	Limit uint64 `json:"limit,omitempty"` // min=1, max=300
+}
+
+// ListReceivedFilesOutput request output.
+type ListReceivedFilesOutput struct {
+	Entries []*SharedFileMetadata `json:"entries"`
+	Cursor  string                `json:"cursor,omitempty"`
+}
+
+// ListReceivedFiles returns the files shared with the current user.
+func (c *Sharing) ListReceivedFiles(in *ListReceivedFilesInput) (out *ListReceivedFilesOutput, err error) {
+	body, err := c.call("/sharing/list_received_files", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// ListReceivedFilesContinueInput request input.
+type ListReceivedFilesContinueInput struct {
+	Cursor string `json:"cursor"`
+}
+
+// ListReceivedFilesContinue pagenates using the cursor from ListReceivedFiles.
+func (c *Sharing) ListReceivedFilesContinue(in *ListReceivedFilesContinueInput) (out *ListReceivedFilesOutput, err error) {
+	body, err := c.call("/sharing/list_received_files/continue", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// RelinquishFileMembershipInput request input.
+type RelinquishFileMembershipInput struct {
+	File string `json:"file"` // Path or ID.
+}
+
+// RelinquishFileMembership removes the current user from a file shared with them.
+func (c *Sharing) RelinquishFileMembership(in *RelinquishFileMembershipInput) (err error) {
+	body, err := c.call("/sharing/relinquish_file_membership", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	return
+}
+
+// AccessInheritance determines whether a folder inherits members from its parent.
+type AccessInheritance string
+
+// AccessInheritance types supported.
+const (
+	AccessInheritanceInherit   AccessInheritance = "inherit"
+	AccessInheritanceNoInherit AccessInheritance = "no_inherit"
+)
+
+// SetAccessInheritanceInput request input.
+type SetAccessInheritanceInput struct {
+	SharedFolderID    string            `json:"shared_folder_id"`
+	AccessInheritance AccessInheritance `json:"access_inheritance"`
+}
+
+// SetAccessInheritance changes whether a folder inherits members from its
+// parent. It may complete asynchronously, see SetAccessInheritanceAndWait.
+func (c *Sharing) SetAccessInheritance(in *SetAccessInheritanceInput) (out *ShareFolderOutput, err error) {
+	body, err := c.call("/sharing/set_access_inheritance", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// SetAccessInheritanceAndWait changes access inheritance, polling the job
+// with backoff until it completes. A failed job returns a *SharingJobError.
+func (c *Sharing) SetAccessInheritanceAndWait(in *SetAccessInheritanceInput) (*SharedFolderMetadata, error) {
+	out, err := c.SetAccessInheritance(in)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.waitForShareJob(out)
+}
+
+// GetFolderMetadataInput request input.
+type GetFolderMetadataInput struct {
+	SharedFolderID string         `json:"shared_folder_id"`
+	Actions        []FolderAction `json:"actions,omitempty"`
+}
+
+// GetFolderMetadataOutput request output.
+type GetFolderMetadataOutput struct {
+	SharedFolderMetadata
+}
+
+// GetFolderMetadata returns the metadata of a shared folder, including
+// permissions for the requested actions.
+func (c *Sharing) GetFolderMetadata(in *GetFolderMetadataInput) (out *GetFolderMetadataOutput, err error) {
+	body, err := c.call("/sharing/get_folder_metadata", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
@@ -2,6 +2,7 @@ package dropbox
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -13,7 +14,32 @@ func TestSharing_CreateSharedLink(t *testing.T) {
 	})
 
 	assert.NoError(t, err, "error sharing file")
-	assert.Equal(t, "/hello.txt", out.Path)
+	assert.Equal(t, "/hello.txt", out.PathLower)
+}
+
+func TestSharing_CreateSharedLink_settings(t *testing.T) {
+	c := client()
+
+	settings := &SharedLinkSettings{
+		Audience: LinkAudiencePublic,
+		Access:   LinkAccessLevelViewer,
+	}
+	settings.SetPassword("secret")
+	settings.SetExpires(time.Now().Add(24 * time.Hour))
+
+	out, err := c.Sharing.CreateSharedLink(&CreateSharedLinkInput{
+		Path:     "/sample.ppt",
+		Settings: settings,
+	})
+	assert.NoError(t, err, "error sharing file")
+	assert.True(t, out.LinkPermissions.RequirePassword)
+	assert.NotNil(t, out.Expires)
+
+	again, err := c.Sharing.CreateSharedLink(&CreateSharedLinkInput{
+		Path: "/sample.ppt",
+	})
+	assert.NoError(t, err, "existing link should be returned")
+	assert.Equal(t, out.URL, again.URL)
 }
 
 func TestSharing_ListSharedFolder(t *testing.T) {
@@ -34,3 +60,201 @@ func TestSharing_ListSharedFolder(t *testing.T) {
 		assert.NotEmpty(t, out.Entries, "output should be non-empty")
 	}
 }
+
+func TestSharing_ModifySharedLinkSettings(t *testing.T) {
+	c := client()
+
+	link, err := c.Sharing.CreateSharedLink(&CreateSharedLinkInput{
+		Path: "/hello.txt",
+	})
+	assert.NoError(t, err)
+
+	out, err := c.Sharing.ModifySharedLinkSettings(&ModifySharedLinkSettingsInput{
+		URL:              link.URL,
+		Settings:         &SharedLinkSettings{Audience: LinkAudienceTeam},
+		RemoveExpiration: true,
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, out.Expires)
+
+	meta, err := c.Sharing.GetSharedLinkMetadata(&GetSharedLinkMetadataInput{
+		URL: link.URL,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "hello.txt", meta.Name)
+
+	err = c.Sharing.RevokeSharedLink(&RevokeSharedLinkInput{URL: link.URL})
+	assert.NoError(t, err)
+}
+
+func TestSharing_ListSharedLinksIter(t *testing.T) {
+	c := client()
+
+	it := c.Sharing.ListSharedLinksIter(&ListShareLinksInput{})
+
+	n := 0
+	for it.Next() {
+		assert.NotEmpty(t, it.Link().URL)
+		n++
+	}
+
+	assert.NoError(t, it.Err())
+	assert.NotZero(t, n)
+}
+
+func TestSharing_GetSharedLinkFile(t *testing.T) {
+	c := client()
+
+	link, err := c.Sharing.CreateSharedLink(&CreateSharedLinkInput{
+		Path: "/list",
+	})
+	assert.NoError(t, err)
+
+	list, err := c.Files.ListFolder(&ListFolderInput{
+		SharedLink: &SharedLink{URL: link.URL},
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, list.Entries)
+
+	name := list.Entries[0].Base().Name
+	out, err := c.Sharing.GetSharedLinkFile(&GetSharedLinkFileInput{
+		URL:  link.URL,
+		Path: "/" + name,
+	})
+	assert.NoError(t, err)
+	defer out.Body.Close()
+
+	assert.Equal(t, name, out.Metadata.Name)
+}
+
+func TestSharing_ShareFolder(t *testing.T) {
+	c := client()
+
+	_, err := c.Files.CreateFolder(&CreateFolderInput{Path: "/shared"})
+	assert.NoError(t, err)
+
+	folder, err := c.Sharing.ShareFolderAndWait(&ShareFolderInput{
+		Path:            "/shared",
+		ACLUpdatePolicy: ACLUpdatePolicyOwner,
+		MemberPolicy:    MemberPolicyAnyone,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "/shared", folder.PathLower)
+
+	err = c.Sharing.AddFolderMember(&AddFolderMemberInput{
+		SharedFolderID: folder.SharedFolderID,
+		Members: []*AddMember{{
+			Member:      NewEmailMember("go-dropbox@example.com"),
+			AccessLevel: Viewer,
+		}},
+		Quiet: true,
+	})
+	assert.NoError(t, err)
+
+	members, err := c.Sharing.ListFolderMembers(&ListFolderMembersInput{
+		SharedFolderID: folder.SharedFolderID,
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, members.Users, "owner should be listed")
+	assert.NotEmpty(t, members.Invitees, "invitee should be listed")
+
+	policy, err := c.Sharing.UpdateFolderPolicy(&UpdateFolderPolicyInput{
+		SharedFolderID:  folder.SharedFolderID,
+		ACLUpdatePolicy: ACLUpdatePolicyEditors,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, ACLUpdatePolicy(ACLUpdatePolicyEditors), policy.Policy.ACLUpdatePolicy.Tag)
+}
+
+func TestSharing_UnshareFolder(t *testing.T) {
+	c := client()
+
+	_, err := c.Files.CreateFolder(&CreateFolderInput{Path: "/unshared"})
+	assert.NoError(t, err)
+
+	folder, err := c.Sharing.ShareFolderAndWait(&ShareFolderInput{
+		Path: "/unshared",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "/unshared", folder.PathLower)
+
+	launch, err := c.Sharing.UnshareFolder(&UnshareFolderInput{
+		SharedFolderID: folder.SharedFolderID,
+		LeaveACopy:     true,
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, c.Sharing.WaitForJob(launch))
+
+	meta, err := c.Files.GetMetadata(&GetMetadataInput{Path: "/unshared"})
+	assert.NoError(t, err)
+	assert.Empty(t, meta.Folder().SharedFolderID, "folder should no longer be shared")
+}
+
+func TestSharing_FileMembers(t *testing.T) {
+	c := client()
+
+	added, err := c.Sharing.AddFileMember(&AddFileMemberInput{
+		File:        "/hello.txt",
+		Members:     []*MemberSelector{NewEmailMember("go-dropbox@example.com")},
+		Quiet:       true,
+		AccessLevel: Viewer,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "success", added[0].Result.Tag)
+
+	in := NewListFileMembersInput()
+	in.File = "/hello.txt"
+
+	members, err := c.Sharing.ListFileMembers(in)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, members.Invitees)
+
+	removed, err := c.Sharing.RemoveFileMember(&RemoveFileMemberInput{
+		File:   "/hello.txt",
+		Member: NewEmailMember("go-dropbox@example.com"),
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "success", removed.Tag)
+}
+
+func TestSharing_ListReceivedFiles(t *testing.T) {
+	c := client()
+
+	_, err := c.Sharing.ListReceivedFiles(&ListReceivedFilesInput{
+		Limit: 10,
+	})
+	assert.NoError(t, err)
+}
+
+func TestSharing_ListSharedFolder_actions(t *testing.T) {
+	c := client()
+	out, err := c.Sharing.ListSharedFolders(&ListSharedFolderInput{
+		Limit:   1,
+		Actions: []FolderAction{FolderActionUnshare, FolderActionInviteEditor},
+	})
+
+	assert.NoError(t, err, "listing shared folders")
+	assert.NotEmpty(t, out.Entries, "output should be non-empty")
+
+	folder := out.Entries[0]
+	assert.NotNil(t, folder.Permission(FolderActionUnshare))
+	assert.Nil(t, folder.Permission(FolderActionCreateLink))
+	assert.False(t, folder.CanPerform(FolderActionCreateLink))
+}
+
+func TestSharing_GetFolderMetadata(t *testing.T) {
+	c := client()
+	list, err := c.Sharing.ListSharedFolders(&ListSharedFolderInput{
+		Limit: 1,
+	})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, list.Entries)
+
+	out, err := c.Sharing.GetFolderMetadata(&GetFolderMetadataInput{
+		SharedFolderID: list.Entries[0].SharedFolderID,
+		Actions:        []FolderAction{FolderActionSetAccessInheritance},
+	})
+	assert.NoError(t, err)
+	assert.NotNil(t, out.Permission(FolderActionSetAccessInheritance))
+	assert.True(t, out.InheritsAccess())
+}
@@ -32,6 +32,12 @@ type GetAccountOutput struct {
 		FamiliarName string `json:"familiar_name"`
 		DisplayName  string `json:"display_name"`
 	} `json:"name"`
+	Email           string `json:"email"`
+	EmailVerified   bool   `json:"email_verified"`
+	Disabled        bool   `json:"disabled"`
+	IsTeammate      bool   `json:"is_teammate"`
+	ProfilePhotoURL string `json:"profile_photo_url,omitempty"`
+	TeamMemberID    string `json:"team_member_id,omitempty"`
 }
 
 // GetAccount returns information about a user's account.
@@ -46,6 +52,50 @@ func (c *Users) GetAccount(in *GetAccountInput) (out *GetAccountOutput, err erro
 	return
 }
 
+// GetAccountBatchMaxIDs is the maximum number of account IDs per batch.
+const GetAccountBatchMaxIDs = 300
+
+// GetAccountBatchInput request input.
+type GetAccountBatchInput struct {
+	AccountIDs []string `json:"account_ids"` // max=300
+}
+
+// GetAccountBatch returns information about multiple user accounts.
+func (c *Users) GetAccountBatch(in *GetAccountBatchInput) (out []*GetAccountOutput, err error) {
+	body, err := c.call("/users/get_account_batch", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
+
+// GetAccounts returns information about any number of user accounts, split
+// into batches of GetAccountBatchMaxIDs. Results are in input order.
+func (c *Users) GetAccounts(ids []string) ([]*GetAccountOutput, error) {
+	var accounts []*GetAccountOutput
+
+	for start := 0; start < len(ids); start += GetAccountBatchMaxIDs {
+		end := start + GetAccountBatchMaxIDs
+		if end > len(ids) {
+			end = len(ids)
+		}
+
+		out, err := c.GetAccountBatch(&GetAccountBatchInput{
+			AccountIDs: ids[start:end],
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		accounts = append(accounts, out...)
+	}
+
+	return accounts, nil
+}
+
 // GetCurrentAccountOutput request output.
 type GetCurrentAccountOutput struct {
 	AccountID string `json:"account_id"`
@@ -55,11 +105,14 @@ type GetCurrentAccountOutput struct {
 		FamiliarName string `json:"familiar_name"`
 		DisplayName  string `json:"display_name"`
 	} `json:"name"`
-	Email        string `json:"email"`
-	Locale       string `json:"locale"`
-	ReferralLink string `json:"referral_link"`
-	IsPaired     bool   `json:"is_paired"`
-	AccountType  struct {
+	Email           string `json:"email"`
+	EmailVerified   bool   `json:"email_verified"`
+	Disabled        bool   `json:"disabled"`
+	ProfilePhotoURL string `json:"profile_photo_url,omitempty"`
+	Locale          string `json:"locale"`
+	ReferralLink    string `json:"referral_link"`
+	IsPaired        bool   `json:"is_paired"`
+	AccountType     struct {
 		Tag string `json:".tag"`
 	} `json:"account_type"`
 	Country string `json:"country"`
@@ -77,13 +130,88 @@ func (c *Users) GetCurrentAccount() (out *GetCurrentAccountOutput, err error) {
 	return
 }
 
+// SpaceAllocationType determines how space is allocated to an account.
+type SpaceAllocationType string
+
+// SpaceAllocationType types supported.
+const (
+	SpaceAllocationIndividual SpaceAllocationType = "individual"
+	SpaceAllocationTeam       SpaceAllocationType = "team"
+	SpaceAllocationOther      SpaceAllocationType = "other" // the allocation is unknown.
+)
+
+// MemberSpaceLimitType determines what happens when a team member exceeds
+// their space limit within the team.
+type MemberSpaceLimitType string
+
+// MemberSpaceLimitType types supported.
+const (
+	MemberSpaceLimitOff       MemberSpaceLimitType = "off"
+	MemberSpaceLimitAlertOnly MemberSpaceLimitType = "alert_only"
+	MemberSpaceLimitStopSync  MemberSpaceLimitType = "stop_sync"
+)
+
+// SpaceAllocation specifies the space allocated to an account. For
+// individual accounts only Allocated is set. For team accounts Used and
+// Allocated describe the whole team, and UserWithinTeamSpaceAllocated is the
+// member's own limit, where 0 means no limit.
+type SpaceAllocation struct {
+	Tag                          SpaceAllocationType `json:".tag"`
+	Used                         uint64              `json:"used,omitempty"`
+	Allocated                    uint64              `json:"allocated"`
+	UserWithinTeamSpaceAllocated uint64              `json:"user_within_team_space_allocated,omitempty"`
+	UserWithinTeamSpaceLimitType *struct {
+		Tag MemberSpaceLimitType `json:".tag"`
+	} `json:"user_within_team_space_limit_type,omitempty"`
+	UserWithinTeamSpaceUsedCached uint64 `json:"user_within_team_space_used_cached,omitempty"`
+}
+
 // GetSpaceUsageOutput request output.
 type GetSpaceUsageOutput struct {
-	Used       uint64 `json:"used"`
-	Allocation struct {
-		Used      uint64 `json:"used"`
-		Allocated uint64 `json:"allocated"`
-	} `json:"allocation"`
+	Used       uint64          `json:"used"`
+	Allocation SpaceAllocation `json:"allocation"`
+}
+
+// Remaining returns the space in bytes the current user can still use. For
+// team accounts this is the team's remaining space, further limited by the
+// member's own limit when it is enforced by stopping sync. The quota is
+// unknown for "other" and unrecognised allocations, in which case ok is false.
+func (o *GetSpaceUsageOutput) Remaining() (n uint64, ok bool) {
+	a := o.Allocation
+
+	if a.Tag == SpaceAllocationIndividual {
+		return remaining(a.Allocated, o.Used), true
+	}
+
+	if a.Tag != SpaceAllocationTeam {
+		return 0, false
+	}
+
+	r := remaining(a.Allocated, a.Used)
+
+	if a.UserWithinTeamSpaceAllocated > 0 && a.UserWithinTeamSpaceLimitType != nil && a.UserWithinTeamSpaceLimitType.Tag == MemberSpaceLimitStopSync {
+		if u := remaining(a.UserWithinTeamSpaceAllocated, o.Used); u < r {
+			r = u
+		}
+	}
+
+	return r, true
+}
+
+// Fits returns true if a file of size bytes fits in the remaining space. It
+// also returns true when the quota is unknown, leaving it to the upload to
+// fail when space runs out.
+func (o *GetSpaceUsageOutput) Fits(size uint64) bool {
+	n, ok := o.Remaining()
+	return !ok || size <= n
+}
+
+// remaining returns allocated - used, or 0 when over quota.
+func remaining(allocated, used uint64) uint64 {
+	if used >= allocated {
+		return 0
+	}
+	return allocated - used
 }
 
 // GetSpaceUsage returns space usage information for the current user's account.
@@ -97,3 +225,41 @@ func (c *Users) GetSpaceUsage() (out *GetSpaceUsageOutput, err error) {
 	err = json.NewDecoder(body).Decode(&out)
 	return
 }
+
+// PhotoSource specifies the image data of a profile photo.
+type PhotoSource struct {
+	Tag        string `json:".tag"`
+	Base64Data []byte `json:"base64_data"` // Encoded as base64.
+}
+
+// SetProfilePhotoInput request input.
+type SetProfilePhotoInput struct {
+	Photo PhotoSource `json:"photo"`
+}
+
+// NewSetProfilePhotoInput creates SetProfilePhotoInput for a JPEG or PNG image.
+func NewSetProfilePhotoInput(image []byte) *SetProfilePhotoInput {
+	return &SetProfilePhotoInput{
+		Photo: PhotoSource{
+			Tag:        "base64_data",
+			Base64Data: image,
+		},
+	}
+}
+
+// SetProfilePhotoOutput request output.
+type SetProfilePhotoOutput struct {
+	ProfilePhotoURL string `json:"profile_photo_url"`
+}
+
+// SetProfilePhoto sets the profile photo of the current user's account.
+func (c *Users) SetProfilePhoto(in *SetProfilePhotoInput) (out *SetProfilePhotoOutput, err error) {
+	body, err := c.call("/account/set_profile_photo", in)
+	if err != nil {
+		return
+	}
+	defer body.Close()
+
+	err = json.NewDecoder(body).Decode(&out)
+	return
+}
@@ -1,6 +1,7 @@
 package dropbox
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -11,3 +12,61 @@ func TestUsers_GetCurrentAccount(t *testing.T) {
 	_, err := c.Users.GetCurrentAccount()
 	assert.NoError(t, err)
 }
+
+func TestUsers_GetAccounts(t *testing.T) {
+	c := client()
+	current, err := c.Users.GetCurrentAccount()
+	assert.NoError(t, err)
+
+	out, err := c.Users.GetAccounts([]string{current.AccountID})
+	assert.NoError(t, err)
+	assert.Len(t, out, 1)
+	assert.Equal(t, current.AccountID, out[0].AccountID)
+	assert.Equal(t, current.EmailVerified, out[0].EmailVerified)
+}
+
+func TestUsers_SetProfilePhoto(t *testing.T) {
+	c := client()
+	out, err := c.Users.SetProfilePhoto(NewSetProfilePhotoInput(grayPng))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, out.ProfilePhotoURL)
+}
+
+func TestUsers_GetSpaceUsage(t *testing.T) {
+	c := client()
+	out, err := c.Users.GetSpaceUsage()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, out.Allocation.Tag)
+	assert.True(t, out.Fits(0))
+}
+
+func TestGetSpaceUsageOutput_Remaining(t *testing.T) {
+	var out GetSpaceUsageOutput
+	err := json.Unmarshal([]byte(`{"used": 300, "allocation": {".tag": "team", "used": 900, "allocated": 1000,
+		"user_within_team_space_allocated": 350, "user_within_team_space_limit_type": {".tag": "stop_sync"},
+		"user_within_team_space_used_cached": 300}}`), &out)
+	assert.NoError(t, err)
+	assert.Equal(t, SpaceAllocationTeam, out.Allocation.Tag)
+	n, ok := out.Remaining()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(50), n)
+	assert.False(t, out.Fits(51))
+
+	out.Allocation.UserWithinTeamSpaceLimitType.Tag = MemberSpaceLimitAlertOnly
+	n, _ = out.Remaining()
+	assert.Equal(t, uint64(100), n)
+
+	var individual GetSpaceUsageOutput
+	err = json.Unmarshal([]byte(`{"used": 1200, "allocation": {".tag": "individual", "allocated": 1000}}`), &individual)
+	assert.NoError(t, err)
+	n, ok = individual.Remaining()
+	assert.True(t, ok)
+	assert.Equal(t, uint64(0), n)
+
+	var other GetSpaceUsageOutput
+	err = json.Unmarshal([]byte(`{"used": 1200, "allocation": {".tag": "other"}}`), &other)
+	assert.NoError(t, err)
+	_, ok = other.Remaining()
+	assert.False(t, ok, "other allocations have an unknown quota")
+	assert.True(t, other.Fits(1<<40))
+}